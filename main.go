@@ -1,10 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"go-gin-test-job/src/config"
 	"go-gin-test-job/src/database"
 	"go-gin-test-job/src/logger"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModule "go-gin-test-job/src/modules/cron"
 	"go-gin-test-job/src/routes"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func init() {
@@ -29,8 +39,46 @@ func main() {
 	if err := database.Connect(); err != nil {
 		logger.Logger.Fatal().Msg("Connect to database error. Error - " + err.Error())
 	}
+	// The runs left running by the previous process can not be finished any more
+	cronModule.InterruptStaleCronRuns()
+	if err := blockchain.InitBalanceResolver(); err != nil {
+		logger.Logger.Fatal().Msg("Init balance resolver error. Error - " + err.Error())
+	}
+	// The signal cancels the scheduled runs and the requests in progress, the runs and the refreshes of the requests with them
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	scheduler, err := cronModule.NewSchedulerFromConfig(ctx, config.AppConfig.CronScheduler, blockchain.Resolver)
+	if err != nil {
+		logger.Logger.Fatal().Msg("Init cron scheduler error. Error - " + err.Error())
+	}
 	app, listenAddress := routes.New()
-	if err := app.Run(listenAddress); err != nil {
-		logger.Logger.Fatal().Msg("Startup error. Error - " + err.Error())
+	server := &http.Server{
+		Addr:    listenAddress,
+		Handler: app,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	if scheduler != nil {
+		scheduler.Start()
+		logger.Logger.Info().Msg("Cron scheduler started")
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Logger.Fatal().Msg("Startup error. Error - " + err.Error())
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Logger.Info().Msg("Shutting down")
+	// The server waits for the cancelled requests, then the scheduler and the runs executed in the background are stopped
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeUtil.DurationSeconds(config.AppConfig.RequestTimeoutSec))
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Logger.Error().Msg("Shutdown error. Error - " + err.Error())
+	}
+	if scheduler != nil {
+		scheduler.Stop()
 	}
+	cronModule.StopAsyncRuns()
 }
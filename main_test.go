@@ -5,6 +5,7 @@ import (
 	"go-gin-test-job/test"
 	testDatabase "go-gin-test-job/test/database"
 	accountTests "go-gin-test-job/test/tests/account"
+	apiKeyTests "go-gin-test-job/test/tests/api-key"
 	cronTests "go-gin-test-job/test/tests/cron"
 	"testing"
 )
@@ -21,4 +22,5 @@ func TestMain(m *testing.M) {
 func TestAllRoutes(t *testing.T) {
 	t.Run("TestAccountRoute", accountTests.TestAccountRoute)
 	t.Run("TestCronRoute", cronTests.TestCronRoute)
+	t.Run("TestApiKeyRoute", apiKeyTests.TestApiKeyRoute)
 }
@@ -1,3 +1,9 @@
+DROP TABLE IF EXISTS api_key;
+DROP TABLE IF EXISTS cron_run;
+DROP TABLE IF EXISTS account_transaction;
+DROP TABLE IF EXISTS account_balance_history;
+DROP TABLE IF EXISTS account_to_tag;
+DROP TABLE IF EXISTS account_tag;
 DROP TABLE IF EXISTS account;
 CREATE TABLE account (
     id BIGINT NOT NULL AUTO_INCREMENT,
@@ -6,11 +12,109 @@ CREATE TABLE account (
     account_rank TINYINT NOT NULL,
     memo TEXT NULL,
     balance DECIMAL(64, 8) NOT NULL DEFAULT 0,
+    unconfirmed_balance DECIMAL(64, 8) NOT NULL DEFAULT 0,
+    total_balance DECIMAL(64, 8) NOT NULL DEFAULT 0,
     status ENUM('On', 'Off') NOT NULL,
     created_at INT NOT NULL,
     updated_at INT NOT NULL,
+    deleted_at INT NOT NULL DEFAULT 0,
+    locked_until INT NOT NULL DEFAULT 0,
+    next_refresh_at INT NOT NULL DEFAULT 0,
+    last_refreshed_at INT NOT NULL DEFAULT 0,
+    refresh_interval_sec INT NOT NULL DEFAULT 0,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(1024) NULL,
     PRIMARY KEY (id),
-    UNIQUE INDEX account_address_unique_idx (address),
+    UNIQUE INDEX account_address_unique_idx (address, deleted_at),
     INDEX account_status_idx (status),
-    INDEX account_updated_at_idx (updated_at)
+    INDEX account_unconfirmed_balance_idx (unconfirmed_balance),
+    INDEX account_updated_at_idx (updated_at),
+    INDEX account_deleted_at_idx (deleted_at),
+    INDEX account_locked_until_idx (locked_until),
+    INDEX account_next_refresh_at_idx (next_refresh_at),
+    FULLTEXT INDEX account_name_memo_fulltext_idx (name, memo)
+);
+
+CREATE TABLE account_tag (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(64) NOT NULL,
+    created_at INT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX account_tag_name_unique_idx (name)
+);
+
+CREATE TABLE account_to_tag (
+    account_id BIGINT NOT NULL,
+    account_tag_id BIGINT NOT NULL,
+    created_at INT NOT NULL,
+    PRIMARY KEY (account_id, account_tag_id),
+    INDEX account_to_tag_account_tag_id_idx (account_tag_id),
+    CONSTRAINT account_to_tag_account_fk FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE,
+    CONSTRAINT account_to_tag_account_tag_fk FOREIGN KEY (account_tag_id) REFERENCES account_tag (id) ON DELETE CASCADE
+);
+
+CREATE TABLE account_balance_history (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    account_id BIGINT NOT NULL,
+    old_balance DECIMAL(64, 8) NOT NULL,
+    new_balance DECIMAL(64, 8) NOT NULL,
+    delta DECIMAL(64, 8) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    created_at INT NOT NULL,
+    PRIMARY KEY (id),
+    INDEX account_balance_history_account_id_created_at_idx (account_id, created_at),
+    CONSTRAINT account_balance_history_account_fk FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE TABLE account_transaction (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    account_id BIGINT NOT NULL,
+    txid VARCHAR(64) NOT NULL,
+    block_height BIGINT NULL,
+    block_time BIGINT NULL,
+    amount_in DECIMAL(64, 8) NOT NULL DEFAULT 0,
+    amount_out DECIMAL(64, 8) NOT NULL DEFAULT 0,
+    fee DECIMAL(64, 8) NOT NULL DEFAULT 0,
+    confirmations BIGINT NOT NULL DEFAULT 0,
+    created_at INT NOT NULL,
+    updated_at INT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX account_transaction_account_id_txid_unique_idx (account_id, txid),
+    INDEX account_transaction_account_id_block_height_idx (account_id, block_height),
+    CONSTRAINT account_transaction_account_fk FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE TABLE cron_run (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    job VARCHAR(64) NOT NULL,
+    triggered_by VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    total INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    updated INT NOT NULL DEFAULT 0,
+    unchanged INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    transactions INT NOT NULL DEFAULT 0,
+    transactions_failed INT NOT NULL DEFAULT 0,
+    report JSON NOT NULL,
+    started_at INT NOT NULL,
+    finished_at INT NOT NULL DEFAULT 0,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    INDEX cron_run_job_started_at_idx (job, started_at),
+    INDEX cron_run_status_started_at_idx (status, started_at)
+);
+
+CREATE TABLE api_key (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(64) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at INT NOT NULL DEFAULT 0,
+    last_used_at INT NOT NULL DEFAULT 0,
+    revoked_at INT NOT NULL DEFAULT 0,
+    created_at INT NOT NULL,
+    PRIMARY KEY (id),
+    INDEX api_key_prefix_idx (prefix)
 );
@@ -1,4 +1,4 @@
-CREATE TRIGGER account_BEFORE_UPDATE BEFORE UPDATE ON account FOR EACH ROW SET new.updated_at = UNIX_TIMESTAMP(NOW());
+-- The updates of an account write updated_at themselves, a cron claim or a failed refresh keeps it
 
 DELIMITER $$
 
@@ -0,0 +1,25 @@
+package errorHelpers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseBadGatewayErrorHTTP struct {
+	Success bool   `json:"success" validate:"required" example:"false"`
+	Message string `json:"message" validate:"required" example:"Blockchain provider error"`
+}
+
+func NewResponseBadGatewayErrorHTTP(message string) *ResponseBadGatewayErrorHTTP {
+	return &ResponseBadGatewayErrorHTTP{
+		Success: false,
+		Message: message,
+	}
+}
+
+func RespondBadGatewayError(c *gin.Context, message string) error {
+	if c != nil {
+		c.JSON(502, NewResponseBadGatewayErrorHTTP(message))
+	}
+	return fmt.Errorf("Bad gateway. %s", message)
+}
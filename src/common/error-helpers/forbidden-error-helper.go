@@ -0,0 +1,25 @@
+package errorHelpers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseForbiddenErrorHTTP struct {
+	Success bool   `json:"success" validate:"required" example:"false"`
+	Message string `json:"message" validate:"required" example:"Forbidden error"`
+}
+
+func NewResponseForbiddenErrorHTTP(message string) *ResponseForbiddenErrorHTTP {
+	return &ResponseForbiddenErrorHTTP{
+		Success: false,
+		Message: message,
+	}
+}
+
+func RespondForbiddenError(c *gin.Context, message string) error {
+	if c != nil {
+		c.JSON(403, NewResponseForbiddenErrorHTTP(message))
+	}
+	return fmt.Errorf("Forbidden. %s", message)
+}
@@ -2,6 +2,7 @@ package validations
 
 import (
 	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
 	"go-gin-test-job/src/database/entities"
 	addressValidationUtil "go-gin-test-job/src/utils/address-validation"
 	"strings"
@@ -25,7 +26,34 @@ func AccountAddressValidation(fl validator.FieldLevel) bool {
 	return false
 }
 
+func DecimalValidation(fl validator.FieldLevel) bool {
+	_, err := decimal.NewFromString(fl.Field().String())
+	return err == nil
+}
+
 func NotEmpty(fl validator.FieldLevel) bool {
 	str := fl.Field().String()
 	return strings.TrimSpace(str) != ""
 }
+
+func AccountTagValidation(fl validator.FieldLevel) bool {
+	return entities.IsValidAccountTagName(entities.NormalizeAccountTagName(fl.Field().String()))
+}
+
+// AccountTagListValidation checks a comma-separated list of tags
+func AccountTagListValidation(fl validator.FieldLevel) bool {
+	names := strings.Split(fl.Field().String(), ",")
+	if len(names) > entities.AccountTagsMaxCount {
+		return false
+	}
+	for _, name := range names {
+		if !entities.IsValidAccountTagName(entities.NormalizeAccountTagName(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func ApiKeyScopeValidation(fl validator.FieldLevel) bool {
+	return entities.IsValidApiKeyScope(fl.Field().String())
+}
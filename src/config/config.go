@@ -7,6 +7,7 @@ import (
 	typeUtil "go-gin-test-job/src/utils/type"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type DbConnectionConfig struct {
@@ -21,6 +22,24 @@ type DbConfig struct {
 	Logging    bool
 }
 
+// BlockchainProviderConfig is one balance provider. An empty Url means the default url of the provider for the network
+type BlockchainProviderConfig struct {
+	Name string
+	Url  string
+}
+
+// BlockchainConfig lists the balance providers in priority order, Quorum above 1 is the count of providers that must agree
+type BlockchainConfig struct {
+	Providers          []BlockchainProviderConfig
+	Network            string
+	Quorum             int
+	RequestsPerSec     int
+	MaxRetries         int
+	RetryBaseDelayMs   int
+	RetryMaxDelayMs    int
+	TransactionsPerRun int
+}
+
 type TestDbConfig struct {
 	Host       string
 	Port       int
@@ -31,17 +50,37 @@ type TestDbConfig struct {
 	Logging    bool
 }
 
+// CronSchedulerConfig runs the balance update inside the server. It is disabled when both the expression and the interval are empty
+type CronSchedulerConfig struct {
+	Expression  string
+	IntervalSec int
+}
+
+// CronRefreshConfig plans the refresh interval of the accounts by rank, the backoff of failures and the failure limit
+type CronRefreshConfig struct {
+	IntervalSec    int
+	MinIntervalSec int
+	MaxBackoffSec  int
+	MaxFailures    int
+}
+
 type Config struct {
-	AppName           string
-	AppHost           string
-	Port              int
-	IsDebug           bool
-	AdminXApiKey      string
-	CronXApiKey       string
-	RequestTimeoutSec int
-	CronBatchCount    int
-	Database          DbConfig
-	TestDatabase      TestDbConfig
+	AppName               string
+	AppHost               string
+	Port                  int
+	IsDebug               bool
+	AdminXApiKey          string
+	CronXApiKey           string
+	RequestTimeoutSec     int
+	CronBatchCount        int
+	CronConcurrency       int
+	CronAccountTimeoutSec int
+	SearchFulltextEnabled bool
+	CronScheduler         CronSchedulerConfig
+	CronRefresh           CronRefreshConfig
+	Blockchain            BlockchainConfig
+	Database              DbConfig
+	TestDatabase          TestDbConfig
 }
 
 var AppConfig *Config
@@ -58,6 +97,42 @@ func LoadConfig() {
 	cronXApiKey := getEnvAsString("CRON_X_API_KEY", nil)
 	requestTimeoutSec := getEnvAsInt("REQUEST_TIMEOUT_SEC", typeUtil.Int(20))
 	cronBatchCount := getEnvAsInt("CRON_BATCH_COUNT", typeUtil.Int(5))
+	cronConcurrency := getEnvAsInt("CRON_CONCURRENCY", typeUtil.Int(5))
+	if cronConcurrency < 1 {
+		logger.Logger.Fatal().Msg("Environment variable CRON_CONCURRENCY must be at least 1")
+	}
+	cronAccountTimeoutSec := getEnvAsInt("CRON_ACCOUNT_TIMEOUT_SEC", typeUtil.Int(60))
+	cronSchedule := strings.TrimSpace(getEnvAsString("CRON_SCHEDULE", typeUtil.String("")))
+	cronIntervalSec := getEnvAsInt("CRON_INTERVAL_SEC", typeUtil.Int(0))
+	if cronSchedule != "" && cronIntervalSec != 0 {
+		logger.Logger.Fatal().Msg("Environment variables CRON_SCHEDULE and CRON_INTERVAL_SEC can not be set both")
+	}
+	cronRefreshIntervalSec := getEnvAsInt("CRON_REFRESH_INTERVAL_SEC", typeUtil.Int(3600))
+	cronRefreshMinIntervalSec := getEnvAsInt("CRON_REFRESH_MIN_INTERVAL_SEC", typeUtil.Int(300))
+	if cronRefreshMinIntervalSec < 0 || cronRefreshMinIntervalSec > cronRefreshIntervalSec {
+		logger.Logger.Fatal().Msg("Environment variable CRON_REFRESH_MIN_INTERVAL_SEC must be between 0 and CRON_REFRESH_INTERVAL_SEC")
+	}
+	cronRefreshMaxBackoffSec := getEnvAsInt("CRON_REFRESH_MAX_BACKOFF_SEC", typeUtil.Int(86400))
+	cronMaxFailures := getEnvAsInt("CRON_MAX_FAILURES", typeUtil.Int(10))
+	if cronMaxFailures < 0 {
+		logger.Logger.Fatal().Msg("Environment variable CRON_MAX_FAILURES must not be negative")
+	}
+	searchFulltextEnabled := getEnvAsBool("SEARCH_FULLTEXT_ENABLED", typeUtil.Bool(true))
+
+	blockchainProviders := getBlockchainProviders(
+		getEnvAsString("BLOCKCHAIN_PROVIDER", typeUtil.String("bitcore")),
+		getEnvAsString("BLOCKCHAIN_PROVIDER_URL", typeUtil.String("")),
+	)
+	blockchainNetwork := getEnvAsString("BLOCKCHAIN_NETWORK", typeUtil.String("mainnet"))
+	blockchainQuorum := getEnvAsInt("BLOCKCHAIN_QUORUM", typeUtil.Int(0))
+	blockchainRequestsPerSec := getEnvAsInt("BLOCKCHAIN_REQUESTS_PER_SEC", typeUtil.Int(5))
+	blockchainMaxRetries := getEnvAsInt("BLOCKCHAIN_MAX_RETRIES", typeUtil.Int(3))
+	blockchainRetryBaseDelayMs := getEnvAsInt("BLOCKCHAIN_RETRY_BASE_DELAY_MS", typeUtil.Int(500))
+	blockchainRetryMaxDelayMs := getEnvAsInt("BLOCKCHAIN_RETRY_MAX_DELAY_MS", typeUtil.Int(10000))
+	blockchainTransactionsPerRun := getEnvAsInt("BLOCKCHAIN_TRANSACTIONS_PER_RUN", typeUtil.Int(200))
+	if blockchainTransactionsPerRun < 0 {
+		logger.Logger.Fatal().Msg("Environment variable BLOCKCHAIN_TRANSACTIONS_PER_RUN must not be negative")
+	}
 
 	dbHost := getEnvAsString("DB_HOST", typeUtil.String("localhost"))
 	dbPort := getEnvAsInt("DB_PORT", typeUtil.Int(3306))
@@ -79,14 +154,37 @@ func LoadConfig() {
 	}
 
 	AppConfig = &Config{
-		AppName:           appName,
-		AppHost:           appHost,
-		Port:              port,
-		IsDebug:           isDebug,
-		AdminXApiKey:      adminXApiKey,
-		CronXApiKey:       cronXApiKey,
-		RequestTimeoutSec: requestTimeoutSec,
-		CronBatchCount:    cronBatchCount,
+		AppName:               appName,
+		AppHost:               appHost,
+		Port:                  port,
+		IsDebug:               isDebug,
+		AdminXApiKey:          adminXApiKey,
+		CronXApiKey:           cronXApiKey,
+		RequestTimeoutSec:     requestTimeoutSec,
+		CronBatchCount:        cronBatchCount,
+		CronConcurrency:       cronConcurrency,
+		CronAccountTimeoutSec: cronAccountTimeoutSec,
+		SearchFulltextEnabled: searchFulltextEnabled,
+		CronScheduler: CronSchedulerConfig{
+			Expression:  cronSchedule,
+			IntervalSec: cronIntervalSec,
+		},
+		CronRefresh: CronRefreshConfig{
+			IntervalSec:    cronRefreshIntervalSec,
+			MinIntervalSec: cronRefreshMinIntervalSec,
+			MaxBackoffSec:  cronRefreshMaxBackoffSec,
+			MaxFailures:    cronMaxFailures,
+		},
+		Blockchain: BlockchainConfig{
+			Providers:          blockchainProviders,
+			Network:            blockchainNetwork,
+			Quorum:             blockchainQuorum,
+			RequestsPerSec:     blockchainRequestsPerSec,
+			MaxRetries:         blockchainMaxRetries,
+			RetryBaseDelayMs:   blockchainRetryBaseDelayMs,
+			RetryMaxDelayMs:    blockchainRetryMaxDelayMs,
+			TransactionsPerRun: blockchainTransactionsPerRun,
+		},
 		Database: DbConfig{
 			Dsn:        dbDns,
 			Connection: defaultDbConnection,
@@ -104,6 +202,24 @@ func LoadConfig() {
 	}
 }
 
+// getBlockchainProviders pairs the comma-separated provider names with the comma-separated urls by position
+func getBlockchainProviders(names string, urls string) []BlockchainProviderConfig {
+	nameList := strings.Split(names, ",")
+	urlList := strings.Split(urls, ",")
+	if len(urlList) > len(nameList) {
+		logger.Logger.Fatal().Msg("Environment variable BLOCKCHAIN_PROVIDER_URL has more urls than BLOCKCHAIN_PROVIDER has providers")
+	}
+	providers := make([]BlockchainProviderConfig, 0, len(nameList))
+	for index, name := range nameList {
+		provider := BlockchainProviderConfig{Name: strings.TrimSpace(name)}
+		if index < len(urlList) {
+			provider.Url = strings.TrimSpace(urlList[index])
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
 func getEnvAsString(key string, defaultValue *string) string {
 	value, exists := os.LookupEnv(key)
 	if !exists {
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const AccountBalanceHistoryTable = "account_balance_history"
+
+// AccountBalanceHistory is a snapshot of one balance update. Delta is negative when the balance decreases
+type AccountBalanceHistory struct {
+	Id         int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccountId  int64           `json:"account_id" gorm:"index:account_balance_history_account_id_created_at_idx,priority:1;not null"`
+	OldBalance decimal.Decimal `json:"old_balance" gorm:"type:decimal(64,8);not null"`
+	NewBalance decimal.Decimal `json:"new_balance" gorm:"type:decimal(64,8);not null"`
+	Delta      decimal.Decimal `json:"delta" gorm:"type:decimal(64,8);not null"`
+	Source     string          `json:"source" gorm:"type:varchar(255);not null"`
+	CreatedAt  int64           `json:"created_at" gorm:"index:account_balance_history_account_id_created_at_idx,priority:2;not null"`
+}
+
+// Set the table name for the model
+func (AccountBalanceHistory) TableName() string {
+	return AccountBalanceHistoryTable
+}
+
+func CreateAccountBalanceHistory(accountId int64, oldBalance decimal.Decimal, newBalance decimal.Decimal, source string, createdAt int64) *AccountBalanceHistory {
+	return &AccountBalanceHistory{
+		AccountId:  accountId,
+		OldBalance: oldBalance,
+		NewBalance: newBalance,
+		Delta:      newBalance.Sub(oldBalance),
+		Source:     source,
+		CreatedAt:  createdAt,
+	}
+}
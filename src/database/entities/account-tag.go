@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const AccountTagTable = "account_tag"
+const AccountToTagTable = "account_to_tag"
+
+const AccountTagNameMaxLength = 64
+const AccountTagsMaxCount = 20
+
+type AccountTag struct {
+	Id        int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string `json:"name" gorm:"uniqueIndex:account_tag_name_unique_idx;type:varchar(64);not null"`
+	CreatedAt int64  `json:"created_at" gorm:"autoCreateTime;not null"`
+}
+
+// Set the table name for the model
+func (AccountTag) TableName() string {
+	return AccountTagTable
+}
+
+// AccountToTag links an account to a tag, an account has every tag at most once
+type AccountToTag struct {
+	AccountId    int64 `json:"account_id" gorm:"primaryKey;autoIncrement:false"`
+	AccountTagId int64 `json:"account_tag_id" gorm:"primaryKey;autoIncrement:false;index:account_to_tag_account_tag_id_idx"`
+	CreatedAt    int64 `json:"created_at" gorm:"autoCreateTime;not null"`
+}
+
+// Set the table name for the model
+func (AccountToTag) TableName() string {
+	return AccountToTagTable
+}
+
+// NormalizeAccountTagName trims and lowercases the name, so "Cold Storage " and "cold storage" are the same tag
+func NormalizeAccountTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// IsValidAccountTagName checks the normalized name. Commas are not allowed since the tags filter is a comma-separated list
+func IsValidAccountTagName(name string) bool {
+	return name != "" && utf8.RuneCountInString(name) <= AccountTagNameMaxLength && !strings.Contains(name, ",")
+}
+
+// NormalizeAccountTagNames normalizes the names and removes the duplicates keeping the order
+func NormalizeAccountTagNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = NormalizeAccountTagName(name)
+		if !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func CreateAccountTag(name string) *AccountTag {
+	return &AccountTag{
+		Name: NormalizeAccountTagName(name),
+	}
+}
+
+func CreateAccountToTag(accountId int64, accountTagId int64) *AccountToTag {
+	return &AccountToTag{
+		AccountId:    accountId,
+		AccountTagId: accountTagId,
+	}
+}
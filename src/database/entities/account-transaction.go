@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"github.com/shopspring/decimal"
+	timeUtils "go-gin-test-job/src/utils/time"
+)
+
+const AccountTransactionTable = "account_transaction"
+
+// AccountTransaction is a blockchain transaction that moved the balance of an account, the block is nil while unconfirmed
+type AccountTransaction struct {
+	Id            int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccountId     int64           `json:"account_id" gorm:"uniqueIndex:account_transaction_account_id_txid_unique_idx,priority:1;index:account_transaction_account_id_block_height_idx,priority:1;not null"`
+	Txid          string          `json:"txid" gorm:"uniqueIndex:account_transaction_account_id_txid_unique_idx,priority:2;type:varchar(64);not null"`
+	BlockHeight   *int64          `json:"block_height" gorm:"index:account_transaction_account_id_block_height_idx,priority:2"`
+	BlockTime     *int64          `json:"block_time"`
+	AmountIn      decimal.Decimal `json:"amount_in" gorm:"type:decimal(64,8);default:0;not null"`
+	AmountOut     decimal.Decimal `json:"amount_out" gorm:"type:decimal(64,8);default:0;not null"`
+	Fee           decimal.Decimal `json:"fee" gorm:"type:decimal(64,8);default:0;not null"`
+	Confirmations int64           `json:"confirmations" gorm:"default:0;not null"`
+	CreatedAt     int64           `json:"created_at" gorm:"not null"`
+	UpdatedAt     int64           `json:"updated_at" gorm:"not null"`
+}
+
+// Set the table name for the model
+func (AccountTransaction) TableName() string {
+	return AccountTransactionTable
+}
+
+func CreateAccountTransaction(accountId int64, txid string, blockHeight *int64, blockTime *int64, amountIn decimal.Decimal, amountOut decimal.Decimal, fee decimal.Decimal, confirmations int64) *AccountTransaction {
+	now := timeUtils.GetUnixTime()
+	return &AccountTransaction{
+		AccountId:     accountId,
+		Txid:          txid,
+		BlockHeight:   blockHeight,
+		BlockTime:     blockTime,
+		AmountIn:      amountIn,
+		AmountOut:     amountOut,
+		Fee:           fee,
+		Confirmations: confirmations,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// IsConfirmed reports whether the transaction is in a block
+func (t *AccountTransaction) IsConfirmed() bool {
+	return t.BlockHeight != nil
+}
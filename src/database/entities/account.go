@@ -16,16 +16,54 @@ const (
 
 var AccountStatusList = []string{string(AccountStatusOn), string(AccountStatusOff)}
 
+type AccountSearchMode string
+
+const (
+	// AccountSearchModeContains matches the term anywhere in the address, name and memo. Works without any index
+	AccountSearchModeContains AccountSearchMode = "contains"
+	// AccountSearchModePrefix matches the start of the address and name
+	AccountSearchModePrefix AccountSearchMode = "prefix"
+	// AccountSearchModeFulltext matches words of the name and memo by the FULLTEXT index and the start of the address
+	AccountSearchModeFulltext AccountSearchMode = "fulltext"
+)
+
+var AccountSearchModeList = []string{string(AccountSearchModeContains), string(AccountSearchModePrefix), string(AccountSearchModeFulltext)}
+
+type AccountTagsMode string
+
+const (
+	// AccountTagsModeAny matches the accounts with at least one of the tags
+	AccountTagsModeAny AccountTagsMode = "any"
+	// AccountTagsModeAll matches the accounts with every one of the tags
+	AccountTagsModeAll AccountTagsMode = "all"
+)
+
+var AccountTagsModeList = []string{string(AccountTagsModeAny), string(AccountTagsModeAll)}
+
+// AccountLastErrorMaxLength is the length the stored refresh error is cut to
+const AccountLastErrorMaxLength = 1024
+
+// Account LockedUntil and NextRefreshAt are unix seconds, RefreshIntervalSec 0 means the interval of its rank
 type Account struct {
-	Id        int64           `json:"id" gorm:"primaryKey;autoIncrement"`
-	Address   string          `json:"address" gorm:"uniqueIndex:account_address_unique_idx;type:varchar(64);not null"`
-	Name      string          `json:"name" gorm:"type:varchar(255);not null"`
-	Rank      int8            `json:"rank" gorm:"column:account_rank;type:tinyint;not null"`
-	Memo      *string         `json:"memo" gorm:"type:text"`
-	Balance   decimal.Decimal `json:"balance" gorm:"type:decimal(64,8);default:0;not null"`
-	Status    AccountStatus   `json:"status" gorm:"index:account_status_idx;type:enum('On','Off');not null"`
-	CreatedAt int64           `json:"created_at" gorm:"autoCreateTime;not null"`
-	UpdatedAt int64           `json:"updated_at" gorm:"autoUpdateTime;index:account_updated_at_idx;not null"`
+	Id                  int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Address             string          `json:"address" gorm:"uniqueIndex:account_address_unique_idx,priority:1;type:varchar(64);not null"`
+	Name                string          `json:"name" gorm:"index:account_name_memo_fulltext_idx,class:FULLTEXT;type:varchar(255);not null"`
+	Rank                int8            `json:"rank" gorm:"column:account_rank;type:tinyint;not null"`
+	Memo                *string         `json:"memo" gorm:"index:account_name_memo_fulltext_idx,class:FULLTEXT;type:text"`
+	Balance             decimal.Decimal `json:"balance" gorm:"type:decimal(64,8);default:0;not null"`
+	UnconfirmedBalance  decimal.Decimal `json:"unconfirmed_balance" gorm:"type:decimal(64,8);index:account_unconfirmed_balance_idx;default:0;not null"`
+	TotalBalance        decimal.Decimal `json:"total_balance" gorm:"type:decimal(64,8);default:0;not null"`
+	Status              AccountStatus   `json:"status" gorm:"index:account_status_idx;type:enum('On','Off');not null"`
+	CreatedAt           int64           `json:"created_at" gorm:"autoCreateTime;not null"`
+	UpdatedAt           int64           `json:"updated_at" gorm:"autoUpdateTime;index:account_updated_at_idx;not null"`
+	DeletedAt           int64           `json:"deleted_at" gorm:"uniqueIndex:account_address_unique_idx,priority:2;index:account_deleted_at_idx;default:0;not null"`
+	LockedUntil         int64           `json:"locked_until" gorm:"index:account_locked_until_idx;default:0;not null"`
+	NextRefreshAt       int64           `json:"next_refresh_at" gorm:"index:account_next_refresh_at_idx;default:0;not null"`
+	LastRefreshedAt     int64           `json:"last_refreshed_at" gorm:"default:0;not null"`
+	RefreshIntervalSec  int             `json:"refresh_interval_sec" gorm:"default:0;not null"`
+	ConsecutiveFailures int             `json:"consecutive_failures" gorm:"default:0;not null"`
+	LastError           *string         `json:"last_error" gorm:"type:varchar(1024)"`
+	Tags                []*AccountTag   `json:"tags" gorm:"many2many:account_to_tag;joinForeignKey:AccountId;joinReferences:AccountTagId"`
 }
 
 // Set the table name for the model
@@ -43,12 +81,46 @@ func CreateAccount(address string, name string, rank int8, memo *string, status
 	}
 }
 
-func (a *Account) UpdateBalance(balance decimal.Decimal) map[string]interface{} {
+// UpdateBalance sets the confirmed and unconfirmed balances from the blockchain and their total
+func (a *Account) UpdateBalance(balance decimal.Decimal, unconfirmedBalance decimal.Decimal, totalBalance decimal.Decimal) map[string]interface{} {
 	a.Balance = balance
+	a.UnconfirmedBalance = unconfirmedBalance
+	a.TotalBalance = totalBalance
 	a.UpdatedAt = timeUtils.GetUnixTime()
 	return map[string]interface{}{
-		"Balance":   a.Balance,
-		"UpdatedAt": a.UpdatedAt,
+		"Balance":            a.Balance,
+		"UnconfirmedBalance": a.UnconfirmedBalance,
+		"TotalBalance":       a.TotalBalance,
+		"UpdatedAt":          a.UpdatedAt,
+	}
+}
+
+// SetRefreshed plans the next refresh after a successful one and clears the failures
+func (a *Account) SetRefreshed(refreshedAt int64, nextRefreshAt int64) map[string]interface{} {
+	a.LastRefreshedAt = refreshedAt
+	a.NextRefreshAt = nextRefreshAt
+	a.ConsecutiveFailures = 0
+	a.LastError = nil
+	return map[string]interface{}{
+		"LastRefreshedAt":     a.LastRefreshedAt,
+		"NextRefreshAt":       a.NextRefreshAt,
+		"ConsecutiveFailures": a.ConsecutiveFailures,
+		"LastError":           a.LastError,
+	}
+}
+
+// SetRefreshFailed counts the failure and plans the retry, the balances and the last refresh time are kept
+func (a *Account) SetRefreshFailed(refreshError string, nextRefreshAt int64) map[string]interface{} {
+	if runes := []rune(refreshError); len(runes) > AccountLastErrorMaxLength {
+		refreshError = string(runes[:AccountLastErrorMaxLength])
+	}
+	a.NextRefreshAt = nextRefreshAt
+	a.ConsecutiveFailures++
+	a.LastError = &refreshError
+	return map[string]interface{}{
+		"NextRefreshAt":       a.NextRefreshAt,
+		"ConsecutiveFailures": a.ConsecutiveFailures,
+		"LastError":           a.LastError,
 	}
 }
 
@@ -60,3 +132,73 @@ func (a *Account) UpdateStatus(status AccountStatus) map[string]interface{} {
 		"UpdatedAt": a.UpdatedAt,
 	}
 }
+
+// IsDeleted reports whether the account is archived. Archived accounts keep their history but release their address
+func (a *Account) IsDeleted() bool {
+	return a.DeletedAt != 0
+}
+
+func (a *Account) Archive() map[string]interface{} {
+	a.DeletedAt = timeUtils.GetUnixTime()
+	a.UpdatedAt = a.DeletedAt
+	return map[string]interface{}{
+		"DeletedAt": a.DeletedAt,
+		"UpdatedAt": a.UpdatedAt,
+	}
+}
+
+func (a *Account) Restore() map[string]interface{} {
+	a.DeletedAt = 0
+	a.UpdatedAt = timeUtils.GetUnixTime()
+	return map[string]interface{}{
+		"DeletedAt": a.DeletedAt,
+		"UpdatedAt": a.UpdatedAt,
+	}
+}
+
+// UpdateDetails an account turned on again starts without failures and is refreshed by the next cron run
+func (a *Account) UpdateDetails(name *string, rank *int8, memo *string, status *AccountStatus, refreshIntervalSec *int) map[string]interface{} {
+	updateData := make(map[string]interface{})
+	if name != nil {
+		a.Name = *name
+		updateData["Name"] = a.Name
+	}
+	if rank != nil {
+		a.Rank = *rank
+		updateData["Rank"] = a.Rank
+	}
+	if memo != nil {
+		a.Memo = memo
+		updateData["Memo"] = a.Memo
+	}
+	if status != nil {
+		if *status == AccountStatusOn && a.Status != AccountStatusOn {
+			a.ConsecutiveFailures = 0
+			a.NextRefreshAt = 0
+			updateData["ConsecutiveFailures"] = a.ConsecutiveFailures
+			updateData["NextRefreshAt"] = a.NextRefreshAt
+		}
+		a.Status = *status
+		updateData["Status"] = a.Status
+	}
+	if refreshIntervalSec != nil {
+		a.RefreshIntervalSec = *refreshIntervalSec
+		updateData["RefreshIntervalSec"] = a.RefreshIntervalSec
+	}
+	a.UpdatedAt = timeUtils.GetUnixTime()
+	updateData["UpdatedAt"] = a.UpdatedAt
+	return updateData
+}
+
+// AccountStatusStats is the count and the total balance of the accounts with one status
+type AccountStatusStats struct {
+	Status  AccountStatus
+	Count   int64
+	Balance decimal.Decimal
+}
+
+// AccountRankBucket is the count of the accounts with the rank in [RankFrom, RankFrom+bucketSize)
+type AccountRankBucket struct {
+	RankFrom int
+	Count    int64
+}
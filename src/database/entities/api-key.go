@@ -0,0 +1,138 @@
+package entities
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	timeUtils "go-gin-test-job/src/utils/time"
+	"slices"
+	"strings"
+)
+
+const ApiKeyTable = "api_key"
+
+const ApiKeyNameMaxLength = 64
+
+// ApiKeyPrefixLength is the count of the first characters of the key kept in plain text
+const ApiKeyPrefixLength = 8
+
+// apiKeySecretBytes the key is the hex of the random bytes
+const apiKeySecretBytes = 32
+
+const (
+	ApiKeyScopeAccountRead  = "account:read"
+	ApiKeyScopeAccountWrite = "account:write"
+	ApiKeyScopeCronRun      = "cron:run"
+	ApiKeyScopeCronRead     = "cron:read"
+	ApiKeyScopeApiKeyManage = "api-key:manage"
+)
+
+var ApiKeyScopeList = []string{
+	ApiKeyScopeAccountRead,
+	ApiKeyScopeAccountWrite,
+	ApiKeyScopeCronRun,
+	ApiKeyScopeCronRead,
+	ApiKeyScopeApiKeyManage,
+}
+
+// ApiKey only the hash of the key is stored, Scopes is a comma-separated list
+type ApiKey struct {
+	Id         int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string `json:"name" gorm:"type:varchar(64);not null"`
+	Prefix     string `json:"prefix" gorm:"index:api_key_prefix_idx;type:varchar(16);not null"`
+	KeyHash    string `json:"-" gorm:"type:char(64);not null"`
+	Scopes     string `json:"scopes" gorm:"type:varchar(255);not null"`
+	ExpiresAt  int64  `json:"expires_at" gorm:"default:0;not null"`
+	LastUsedAt int64  `json:"last_used_at" gorm:"default:0;not null"`
+	RevokedAt  int64  `json:"revoked_at" gorm:"default:0;not null"`
+	CreatedAt  int64  `json:"created_at" gorm:"autoCreateTime;not null"`
+}
+
+// Set the table name for the model
+func (ApiKey) TableName() string {
+	return ApiKeyTable
+}
+
+// CreateApiKey generates a new key and returns it with the entity, the key can not be read from the entity later
+func CreateApiKey(name string, scopes []string, expiresAt int64) (*ApiKey, string, error) {
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	key := hex.EncodeToString(secret)
+	return &ApiKey{
+		Name:      strings.TrimSpace(name),
+		Prefix:    GetApiKeyPrefix(key),
+		KeyHash:   HashApiKey(key),
+		Scopes:    strings.Join(NormalizeApiKeyScopes(scopes), ","),
+		ExpiresAt: expiresAt,
+	}, key, nil
+}
+
+// HashApiKey a plain sha256 is enough, the keys are random and not guessable like passwords
+func HashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// GetApiKeyPrefix is empty for a key shorter than the prefix, such a key is never generated
+func GetApiKeyPrefix(key string) string {
+	if len(key) < ApiKeyPrefixLength {
+		return ""
+	}
+	return key[:ApiKeyPrefixLength]
+}
+
+func IsValidApiKeyScope(scope string) bool {
+	return slices.Contains(ApiKeyScopeList, scope)
+}
+
+// NormalizeApiKeyScopes removes the duplicates and the unknown scopes and keeps the order of the scope list
+func NormalizeApiKeyScopes(scopes []string) []string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range ApiKeyScopeList {
+		if slices.Contains(scopes, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+// Matches compares the hashes in constant time
+func (k *ApiKey) Matches(key string) bool {
+	return subtle.ConstantTimeCompare([]byte(k.KeyHash), []byte(HashApiKey(key))) == 1
+}
+
+func (k *ApiKey) GetScopes() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k *ApiKey) IsRevoked() bool {
+	return k.RevokedAt != 0
+}
+
+func (k *ApiKey) IsExpired(now int64) bool {
+	return k.ExpiresAt != 0 && k.ExpiresAt <= now
+}
+
+func (k *ApiKey) IsActive(now int64) bool {
+	return !k.IsRevoked() && !k.IsExpired(now)
+}
+
+func (k *ApiKey) Revoke() map[string]interface{} {
+	k.RevokedAt = timeUtils.GetUnixTime()
+	return map[string]interface{}{
+		"RevokedAt": k.RevokedAt,
+	}
+}
+
+func (k *ApiKey) SetUsed(usedAt int64) map[string]interface{} {
+	k.LastUsedAt = usedAt
+	return map[string]interface{}{
+		"LastUsedAt": k.LastUsedAt,
+	}
+}
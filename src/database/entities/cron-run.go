@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"time"
+)
+
+const CronRunTable = "cron_run"
+
+const (
+	CronRunJobAccountBalance = "account-balance"
+)
+
+const (
+	CronRunTriggerHttp      = "http"
+	CronRunTriggerScheduler = "scheduler"
+)
+
+const (
+	CronRunStatusRunning = "running"
+	CronRunStatusSuccess = "success"
+	CronRunStatusPartial = "partial"
+	CronRunStatusFailed  = "failed"
+	// CronRunStatusInterrupted is a run whose process stopped or that was cancelled before the run was finished
+	CronRunStatusInterrupted = "interrupted"
+)
+
+// CronRun is the report of one run of a cron job, FinishedAt is 0 while the run is running
+type CronRun struct {
+	Id                 int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Job                string `json:"job" gorm:"index:cron_run_job_started_at_idx,priority:1;type:varchar(64);not null"`
+	TriggeredBy        string `json:"triggered_by" gorm:"type:varchar(16);not null"`
+	Status             string `json:"status" gorm:"index:cron_run_status_started_at_idx,priority:1;type:varchar(16);not null"`
+	Total              int    `json:"total" gorm:"default:0;not null"`
+	Processed          int    `json:"processed" gorm:"default:0;not null"`
+	Updated            int    `json:"updated" gorm:"default:0;not null"`
+	Unchanged          int    `json:"unchanged" gorm:"default:0;not null"`
+	Failed             int    `json:"failed" gorm:"default:0;not null"`
+	Transactions       int    `json:"transactions" gorm:"default:0;not null"`
+	TransactionsFailed int    `json:"transactions_failed" gorm:"default:0;not null"`
+	Report             string `json:"report" gorm:"type:json;not null"`
+	StartedAt          int64  `json:"started_at" gorm:"index:cron_run_job_started_at_idx,priority:2;index:cron_run_status_started_at_idx,priority:2;not null"`
+	FinishedAt         int64  `json:"finished_at" gorm:"default:0;not null"`
+	DurationMs         int64  `json:"duration_ms" gorm:"default:0;not null"`
+}
+
+// Set the table name for the model
+func (CronRun) TableName() string {
+	return CronRunTable
+}
+
+// CreateCronRun is a running run, the counts are set by the job
+func CreateCronRun(job string, triggeredBy string, total int, startedAt time.Time) *CronRun {
+	return &CronRun{
+		Job:         job,
+		TriggeredBy: triggeredBy,
+		Status:      CronRunStatusRunning,
+		Total:       total,
+		Report:      "{}",
+		StartedAt:   startedAt.Unix(),
+	}
+}
+
+func (r *CronRun) IsFinished() bool {
+	return r.Status != CronRunStatusRunning
+}
+
+// Progress is the counts and the report of the run so far
+func (r *CronRun) Progress() map[string]interface{} {
+	return map[string]interface{}{
+		"Processed":          r.Processed,
+		"Updated":            r.Updated,
+		"Unchanged":          r.Unchanged,
+		"Failed":             r.Failed,
+		"Transactions":       r.Transactions,
+		"TransactionsFailed": r.TransactionsFailed,
+		"Report":             r.Report,
+	}
+}
+
+// Finish sets the status and the times of the run together with its final counts
+func (r *CronRun) Finish(startedAt time.Time, finishedAt time.Time) map[string]interface{} {
+	r.SetStatus()
+	return r.setFinished(startedAt, finishedAt)
+}
+
+// Interrupt finishes a run that was cancelled before all its accounts were processed
+func (r *CronRun) Interrupt(startedAt time.Time, finishedAt time.Time) map[string]interface{} {
+	r.Status = CronRunStatusInterrupted
+	return r.setFinished(startedAt, finishedAt)
+}
+
+func (r *CronRun) setFinished(startedAt time.Time, finishedAt time.Time) map[string]interface{} {
+	r.FinishedAt = finishedAt.Unix()
+	r.DurationMs = finishedAt.Sub(startedAt).Milliseconds()
+	updateData := r.Progress()
+	updateData["Status"] = r.Status
+	updateData["FinishedAt"] = r.FinishedAt
+	updateData["DurationMs"] = r.DurationMs
+	return updateData
+}
+
+// SetStatus is failed when no account was updated, partial when some balances or transactions failed
+func (r *CronRun) SetStatus() {
+	switch {
+	case r.Processed > 0 && r.Failed == r.Processed:
+		r.Status = CronRunStatusFailed
+	case r.Failed > 0 || r.TransactionsFailed > 0:
+		r.Status = CronRunStatusPartial
+	default:
+		r.Status = CronRunStatusSuccess
+	}
+}
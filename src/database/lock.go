@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DbLock is a MySQL named lock held on its own connection until Release
+type DbLock struct {
+	name string
+	conn *sql.Conn
+}
+
+// TryLock does not wait, it returns nil without an error when another session holds the lock
+func TryLock(ctx context.Context, name string) (*DbLock, error) {
+	sqlDB, err := DbConn.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(DATABASE(), ':', ?), 0)", name).Scan(&result); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if !result.Valid || result.Int64 != 1 {
+		_ = conn.Close()
+		return nil, nil
+	}
+	return &DbLock{name: name, conn: conn}, nil
+}
+
+func (l *DbLock) Release() error {
+	defer l.conn.Close()
+	_, err := l.conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(CONCAT(DATABASE(), ':', ?))", l.name)
+	return err
+}
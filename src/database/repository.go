@@ -1,15 +1,49 @@
 package database
 
 import (
-	"fmt"
+	"github.com/shopspring/decimal"
 	"go-gin-test-job/src/database/entities"
+	orderUtil "go-gin-test-job/src/utils/order"
+	stringUtil "go-gin-test-job/src/utils/string"
+	timeUtils "go-gin-test-job/src/utils/time"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
 )
 
+// accountOrderColumns maps sort fields to columns when their names differ
+var accountOrderColumns = map[string]string{
+	"rank": "account_rank",
+}
+
 func accountTableName() string {
 	return entities.Account{}.TableName()
 }
 
+func accountTagTableName() string {
+	return entities.AccountTag{}.TableName()
+}
+
+func accountToTagTableName() string {
+	return entities.AccountToTag{}.TableName()
+}
+
+func accountBalanceHistoryTableName() string {
+	return entities.AccountBalanceHistory{}.TableName()
+}
+
+func accountTransactionTableName() string {
+	return entities.AccountTransaction{}.TableName()
+}
+
+func cronRunTableName() string {
+	return entities.CronRun{}.TableName()
+}
+
+func apiKeyTableName() string {
+	return entities.ApiKey{}.TableName()
+}
+
 func getDb(tx *gorm.DB) *gorm.DB {
 	var db *gorm.DB
 	if tx != nil {
@@ -22,15 +56,35 @@ func getDb(tx *gorm.DB) *gorm.DB {
 
 ///// Account queries
 
-func GetAccountsAndTotal(status entities.AccountStatus, orderParams map[string]string, offset int, count int, search string) ([]*entities.Account, int64) {
+const accountFulltextMatch = "MATCH(account.name, account.memo) AGAINST (? IN NATURAL LANGUAGE MODE)"
+
+// AccountFilter holds the filters of the account list, nil and empty values are not applied
+type AccountFilter struct {
+	Status         entities.AccountStatus
+	Search         string
+	SearchMode     entities.AccountSearchMode
+	IncludeDeleted bool
+	Tags           []string
+	TagsMode       entities.AccountTagsMode
+	RankMin        *int8
+	RankMax        *int8
+	BalanceMin     *decimal.Decimal
+	BalanceMax     *decimal.Decimal
+	CreatedFrom    *int64
+	CreatedTo      *int64
+	UpdatedFrom    *int64
+	UpdatedTo      *int64
+	PendingFunds   bool
+}
+
+func GetAccountsAndTotal(filter AccountFilter, orderParams []orderUtil.OrderParam, offset int, count int) ([]*entities.Account, int64) {
 	var total int64
 	var accounts []*entities.Account
-	query := getBaseAccountsQuery(status, search)
-	totalQuery := getBaseAccountsQuery(status, search)
-	for key, value := range orderParams {
-		query = query.Order(fmt.Sprintf("account.%s %s", key, value))
-	}
+	query := getBaseAccountsQuery(filter)
+	totalQuery := getBaseAccountsQuery(filter)
+	query = applyAccountsOrder(query, filter, orderParams)
 	query.
+		Scopes(preloadAccountTags).
 		Limit(count).
 		Offset(offset).
 		Find(&accounts)
@@ -38,23 +92,223 @@ func GetAccountsAndTotal(status entities.AccountStatus, orderParams map[string]s
 	return accounts, total
 }
 
-func getBaseAccountsQuery(status entities.AccountStatus, search string) *gorm.DB {
+// GetAccountsAfterCursor returns the page of accounts that follows the row with the given sort values
+func GetAccountsAfterCursor(filter AccountFilter, orderParams []orderUtil.OrderParam, cursorValues []string, count int) []*entities.Account {
+	var accounts []*entities.Account
+	orderParams = WithAccountsOrderTiebreaker(orderParams)
+	query := getBaseAccountsQuery(filter)
+	keysetCondition, keysetArgs := getAccountsKeysetCondition(orderParams, cursorValues)
+	query = query.Where(keysetCondition, keysetArgs...)
+	query = applyAccountsOrder(query, filter, orderParams)
+	query.
+		Scopes(preloadAccountTags).
+		Limit(count).
+		Find(&accounts)
+	return accounts
+}
+
+// IterateAccounts streams the filtered accounts row by row to the callback without loading them all in memory
+func IterateAccounts(filter AccountFilter, orderParams []orderUtil.OrderParam, callback func(account *entities.Account) error) error {
+	query := getBaseAccountsQuery(filter)
+	query = applyAccountsOrder(query, filter, orderParams)
+	rows, err := query.Select("account.*").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var account entities.Account
+		if err := DbConn.ScanRows(rows, &account); err != nil {
+			return err
+		}
+		if err := callback(&account); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// WithAccountsOrderTiebreaker appends the id to the order params so that the order of accounts is always total
+func WithAccountsOrderTiebreaker(orderParams []orderUtil.OrderParam) []orderUtil.OrderParam {
+	if orderUtil.HasOrderField(orderParams, "id") {
+		return orderParams
+	}
+	result := make([]orderUtil.OrderParam, 0, len(orderParams)+1)
+	result = append(result, orderParams...)
+	return append(result, orderUtil.OrderParam{Field: "id", Direction: "ASC"})
+}
+
+// IsAccountsRelevanceOrder tells whether the accounts are sorted by the fulltext relevance, which happens when nothing else is asked for
+func IsAccountsRelevanceOrder(filter AccountFilter, orderParams []orderUtil.OrderParam) bool {
+	return filter.SearchMode == entities.AccountSearchModeFulltext && filter.Search != "" && len(orderParams) == 0
+}
+
+func applyAccountsOrder(query *gorm.DB, filter AccountFilter, orderParams []orderUtil.OrderParam) *gorm.DB {
+	if IsAccountsRelevanceOrder(filter, orderParams) {
+		// The whole ORDER BY goes in one expression, gorm drops an expression when columns are merged after it
+		return query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  accountFulltextMatch + " DESC, account.id ASC",
+			Vars: []interface{}{filter.Search},
+		}})
+	}
+	for _, orderParam := range WithAccountsOrderTiebreaker(orderParams) {
+		query = query.Order(accountOrderColumn(orderParam.Field) + " " + orderParam.Direction)
+	}
+	return query
+}
+
+func accountOrderColumn(field string) string {
+	if column, exists := accountOrderColumns[field]; exists {
+		return "account." + column
+	}
+	return "account." + field
+}
+
+// getAccountsKeysetCondition builds "(a > ?) OR (a = ? AND b > ?) OR ..." for the rows after the cursor
+func getAccountsKeysetCondition(orderParams []orderUtil.OrderParam, cursorValues []string) (string, []interface{}) {
+	conditions := make([]string, 0, len(orderParams))
+	args := make([]interface{}, 0)
+	for i, orderParam := range orderParams {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, accountOrderColumn(orderParams[j].Field)+" = ?")
+			args = append(args, cursorValues[j])
+		}
+		operator := ">"
+		if orderParam.Direction == "DESC" {
+			operator = "<"
+		}
+		parts = append(parts, accountOrderColumn(orderParam.Field)+" "+operator+" ?")
+		args = append(args, cursorValues[i])
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+func getBaseAccountsQuery(filter AccountFilter) *gorm.DB {
 	query := DbConn.Table(accountTableName() + " account")
-	if status != "" {
-		query = query.Where("account.status = ?", status)
+	if !filter.IncludeDeleted {
+		query = query.Where("account.deleted_at = 0")
+	}
+	if filter.Status != "" {
+		query = query.Where("account.status = ?", filter.Status)
+	}
+	if filter.Search != "" {
+		query = applyAccountsSearch(query, filter.Search, filter.SearchMode)
+	}
+	if len(filter.Tags) > 0 {
+		query = applyAccountsTags(query, filter.Tags, filter.TagsMode)
+	}
+	if filter.RankMin != nil {
+		query = query.Where("account.account_rank >= ?", *filter.RankMin)
+	}
+	if filter.RankMax != nil {
+		query = query.Where("account.account_rank <= ?", *filter.RankMax)
 	}
-	if search != "" {
-		searchTerm := "%" + search + "%"
-		query = query.Where("account.address LIKE ? OR account.name LIKE ? OR account.memo LIKE ?", searchTerm, searchTerm, searchTerm)
+	if filter.BalanceMin != nil {
+		query = query.Where("account.balance >= ?", *filter.BalanceMin)
+	}
+	if filter.BalanceMax != nil {
+		query = query.Where("account.balance <= ?", *filter.BalanceMax)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("account.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("account.created_at <= ?", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		query = query.Where("account.updated_at >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		query = query.Where("account.updated_at <= ?", *filter.UpdatedTo)
+	}
+	if filter.PendingFunds {
+		query = query.Where("account.unconfirmed_balance > 0")
 	}
 	return query
 }
 
+func applyAccountsSearch(query *gorm.DB, search string, searchMode entities.AccountSearchMode) *gorm.DB {
+	escapedSearch := stringUtil.EscapeLike(search)
+	switch searchMode {
+	case entities.AccountSearchModePrefix:
+		searchTerm := escapedSearch + "%"
+		return query.Where("(account.address LIKE ? OR account.name LIKE ?)", searchTerm, searchTerm)
+	case entities.AccountSearchModeFulltext:
+		return query.Where("("+accountFulltextMatch+" OR account.address LIKE ?)", search, escapedSearch+"%")
+	}
+	searchTerm := "%" + escapedSearch + "%"
+	return query.Where("(account.address LIKE ? OR account.name LIKE ? OR account.memo LIKE ?)", searchTerm, searchTerm, searchTerm)
+}
+
+func GetAccountsStatusStats(filter AccountFilter) []*entities.AccountStatusStats {
+	var stats []*entities.AccountStatusStats
+	getBaseAccountsQuery(filter).
+		Select("account.status AS status, COUNT(*) AS count, COALESCE(SUM(account.balance), 0) AS balance").
+		Group("account.status").
+		Scan(&stats)
+	return stats
+}
+
+// GetAccountsRankHistogram returns only the non-empty buckets ordered by rank
+func GetAccountsRankHistogram(filter AccountFilter, bucketSize int) []*entities.AccountRankBucket {
+	var buckets []*entities.AccountRankBucket
+	getBaseAccountsQuery(filter).
+		Select("FLOOR(account.account_rank / ?) * ? AS rank_from, COUNT(*) AS count", bucketSize, bucketSize).
+		Group("rank_from").
+		Order("rank_from ASC").
+		Scan(&buckets)
+	return buckets
+}
+
+func GetTopAccountsByBalance(filter AccountFilter, count int) []*entities.Account {
+	var accounts []*entities.Account
+	query := getBaseAccountsQuery(filter)
+	query = applyAccountsOrder(query, filter, []orderUtil.OrderParam{{Field: "balance", Direction: "DESC"}})
+	query.
+		Scopes(preloadAccountTags).
+		Limit(count).
+		Find(&accounts)
+	return accounts
+}
+
+// GetOldestAccountUpdatedAt returns nil if there is no account with the On status among the filtered ones
+func GetOldestAccountUpdatedAt(filter AccountFilter) *int64 {
+	var oldestUpdatedAt *int64
+	getBaseAccountsQuery(filter).
+		Where("account.status = ?", entities.AccountStatusOn).
+		Select("MIN(account.updated_at)").
+		Row().
+		Scan(&oldestUpdatedAt)
+	return oldestUpdatedAt
+}
+
+// applyAccountsTags keeps the accounts with any or all of the tags. The tag names must be normalized
+func applyAccountsTags(query *gorm.DB, tags []string, tagsMode entities.AccountTagsMode) *gorm.DB {
+	subQuery := DbConn.Table(accountToTagTableName()+" account_to_tag").
+		Select("account_to_tag.account_id").
+		Joins("JOIN "+accountTagTableName()+" account_tag ON account_tag.id = account_to_tag.account_tag_id").
+		Where("account_tag.name IN ?", tags)
+	if tagsMode == entities.AccountTagsModeAll {
+		subQuery = subQuery.
+			Group("account_to_tag.account_id").
+			Having("COUNT(DISTINCT account_tag.id) = ?", len(tags))
+	}
+	return query.Where("account.id IN (?)", subQuery)
+}
+
+func preloadAccountTags(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("account_tag.name ASC")
+	})
+}
+
 func IsAddressExists(tx *gorm.DB, address string) bool {
 	db := getDb(tx)
 	var account *entities.Account
 	db.Table(accountTableName()+" account").
-		Where("account.address = ?", address).
+		Where("account.address = ? AND account.deleted_at = 0", address).
 		First(&account)
 	if account.Id != 0 {
 		return true
@@ -62,10 +316,37 @@ func IsAddressExists(tx *gorm.DB, address string) bool {
 	return false
 }
 
+// GetAccountById an archived account is not found, like by its address
+func GetAccountById(tx *gorm.DB, id int64) *entities.Account {
+	return getAccountById(tx, id, false)
+}
+
+// GetAccountByIdIncludeDeleted finds an archived account as well, it is used to restore the account
+func GetAccountByIdIncludeDeleted(tx *gorm.DB, id int64) *entities.Account {
+	return getAccountById(tx, id, true)
+}
+
+func getAccountById(tx *gorm.DB, id int64, includeDeleted bool) *entities.Account {
+	db := getDb(tx)
+	var account *entities.Account
+	query := db.Table(accountTableName()+" account").
+		Scopes(preloadAccountTags).
+		Where("account.id = ?", id)
+	if !includeDeleted {
+		query = query.Where("account.deleted_at = 0")
+	}
+	query.First(&account)
+	if account.Id == 0 {
+		return nil
+	}
+	return account
+}
+
 func GetAccountByAddress(address string) *entities.Account {
 	var account *entities.Account
 	DbConn.Table(accountTableName()+" account").
-		Where("account.address = ?", address).
+		Scopes(preloadAccountTags).
+		Where("account.address = ? AND account.deleted_at = 0", address).
 		First(&account)
 	if account.Id == 0 {
 		return nil
@@ -81,16 +362,73 @@ func CreateAccount(tx *gorm.DB, newAccount *entities.Account) (*entities.Account
 	return newAccount, nil
 }
 
+// GetAccountsBatch returns the accounts the next cron run would claim
 func GetAccountsBatch(limit int) []*entities.Account {
 	var accounts []*entities.Account
-	DbConn.Table(accountTableName()+" account").
-		Where("account.status = ?", entities.AccountStatusOn).
-		Order("account.updated_at ASC").
+	getAccountsBatchQuery(DbConn, timeUtils.GetUnixTime()).
 		Limit(limit).
 		Find(&accounts)
 	return accounts
 }
 
+// ClaimAccountsBatch claims the accounts due for a refresh until lockedUntil, skipping the rows claimed by a parallel run
+func ClaimAccountsBatch(limit int, lockedUntil int64) ([]*entities.Account, error) {
+	return claimAccounts(lockedUntil, func(tx *gorm.DB) *gorm.DB {
+		return getAccountsBatchQuery(tx, timeUtils.GetUnixTime()).Limit(limit)
+	})
+}
+
+// ClaimAccountsByIds claims the accounts that are not archived and not claimed at the moment
+func ClaimAccountsByIds(accountIds []int64, lockedUntil int64) ([]*entities.Account, error) {
+	if len(accountIds) == 0 {
+		return []*entities.Account{}, nil
+	}
+	return claimAccounts(lockedUntil, func(tx *gorm.DB) *gorm.DB {
+		return tx.Table(accountTableName()+" account").
+			Where("account.id IN ? AND account.deleted_at = 0 AND account.locked_until < ?", accountIds, timeUtils.GetUnixTime()).
+			Order("account.id ASC")
+	})
+}
+
+func claimAccounts(lockedUntil int64, query func(tx *gorm.DB) *gorm.DB) ([]*entities.Account, error) {
+	var accounts []*entities.Account
+	err := DbConn.Transaction(func(tx *gorm.DB) error {
+		err := query(tx).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&accounts).Error
+		if err != nil || len(accounts) == 0 {
+			return err
+		}
+		accountIds := make([]int64, 0, len(accounts))
+		for _, account := range accounts {
+			accountIds = append(accountIds, account.Id)
+			account.LockedUntil = lockedUntil
+		}
+		// The claim is not a change of the account, updated_at is kept
+		return tx.Model(entities.Account{}).Where("id IN ?", accountIds).UpdateColumn("locked_until", lockedUntil).Error
+	}, DefaultTxOptions)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// ReleaseAccounts ends the claim of a run. An account claimed again by another run after the claim expired keeps the new claim
+func ReleaseAccounts(accountIds []int64, lockedUntil int64) error {
+	if len(accountIds) == 0 {
+		return nil
+	}
+	return DbConn.Model(entities.Account{}).
+		Where("id IN ? AND locked_until = ?", accountIds, lockedUntil).
+		UpdateColumn("locked_until", 0).Error
+}
+
+func getAccountsBatchQuery(db *gorm.DB, now int64) *gorm.DB {
+	return db.Table(accountTableName()+" account").
+		Where("account.status = ? AND account.deleted_at = 0 AND account.locked_until < ? AND account.next_refresh_at <= ?", entities.AccountStatusOn, now, now).
+		Order("account.next_refresh_at ASC, account.id ASC")
+}
+
 func GetAccountsByIds(accountIds []int64) []*entities.Account {
 	var accounts []*entities.Account
 	DbConn.Table(accountTableName()+" account").
@@ -103,3 +441,300 @@ func UpdateAccount(tx *gorm.DB, account *entities.Account, updateData map[string
 	db := getDb(tx)
 	return db.Model(entities.Account{}).Where("id = ?", account.Id).Updates(updateData).Error
 }
+
+// UpdateAccountColumns writes only the given columns, updated_at is kept unless it is given
+func UpdateAccountColumns(tx *gorm.DB, account *entities.Account, updateData map[string]interface{}) error {
+	db := getDb(tx)
+	return db.Model(entities.Account{}).Where("id = ?", account.Id).UpdateColumns(updateData).Error
+}
+
+///// Account tag queries
+
+// GetOrCreateAccountTags returns the tags with the names, the missing ones are created. The names must be normalized
+func GetOrCreateAccountTags(tx *gorm.DB, names []string) ([]*entities.AccountTag, error) {
+	db := getDb(tx)
+	newTags := make([]*entities.AccountTag, 0, len(names))
+	for _, name := range names {
+		newTags = append(newTags, entities.CreateAccountTag(name))
+	}
+	// A tag created concurrently by another request is not an error
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error
+	if err != nil {
+		return nil, err
+	}
+	var tags []*entities.AccountTag
+	err = db.Table(accountTagTableName()+" account_tag").
+		Where("account_tag.name IN ?", names).
+		Order("account_tag.name ASC").
+		Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func GetAccountTagByName(tx *gorm.DB, name string) *entities.AccountTag {
+	db := getDb(tx)
+	var tag *entities.AccountTag
+	db.Table(accountTagTableName()+" account_tag").
+		Where("account_tag.name = ?", name).
+		First(&tag)
+	if tag.Id == 0 {
+		return nil
+	}
+	return tag
+}
+
+func GetAccountTags(tx *gorm.DB, accountId int64) []*entities.AccountTag {
+	db := getDb(tx)
+	var tags []*entities.AccountTag
+	db.Table(accountTagTableName()+" account_tag").
+		Joins("JOIN "+accountToTagTableName()+" account_to_tag ON account_to_tag.account_tag_id = account_tag.id").
+		Where("account_to_tag.account_id = ?", accountId).
+		Order("account_tag.name ASC").
+		Find(&tags)
+	return tags
+}
+
+// AddAccountTags links the tags to the account, the tags the account already has are skipped
+func AddAccountTags(tx *gorm.DB, accountId int64, tags []*entities.AccountTag) error {
+	db := getDb(tx)
+	links := make([]*entities.AccountToTag, 0, len(tags))
+	for _, tag := range tags {
+		links = append(links, entities.CreateAccountToTag(accountId, tag.Id))
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+// RemoveAccountTag returns false if the account does not have the tag
+func RemoveAccountTag(tx *gorm.DB, accountId int64, accountTagId int64) (bool, error) {
+	db := getDb(tx)
+	result := db.Where("account_id = ? AND account_tag_id = ?", accountId, accountTagId).Delete(&entities.AccountToTag{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+///// Account balance history queries
+
+// AccountBalanceHistoryFilter holds the filters of the balance history. Nil values are not applied
+type AccountBalanceHistoryFilter struct {
+	AccountId   int64
+	CreatedFrom *int64
+	CreatedTo   *int64
+}
+
+func CreateAccountBalanceHistory(tx *gorm.DB, history *entities.AccountBalanceHistory) error {
+	db := getDb(tx)
+	return db.Create(history).Error
+}
+
+// GetAccountBalanceHistoryAndTotal returns the newest snapshots first
+func GetAccountBalanceHistoryAndTotal(filter AccountBalanceHistoryFilter, offset int, count int) ([]*entities.AccountBalanceHistory, int64) {
+	var total int64
+	var histories []*entities.AccountBalanceHistory
+	query := getBaseAccountBalanceHistoryQuery(filter)
+	totalQuery := getBaseAccountBalanceHistoryQuery(filter)
+	query.
+		Order("account_balance_history.created_at DESC").
+		Order("account_balance_history.id DESC").
+		Limit(count).
+		Offset(offset).
+		Find(&histories)
+	totalQuery.Count(&total)
+	return histories, total
+}
+
+func getBaseAccountBalanceHistoryQuery(filter AccountBalanceHistoryFilter) *gorm.DB {
+	query := DbConn.Table(accountBalanceHistoryTableName()+" account_balance_history").
+		Where("account_balance_history.account_id = ?", filter.AccountId)
+	if filter.CreatedFrom != nil {
+		query = query.Where("account_balance_history.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("account_balance_history.created_at <= ?", *filter.CreatedTo)
+	}
+	return query
+}
+
+///// Account transaction queries
+
+// GetAccountLastTransactionBlockHeight returns the height of the newest confirmed transaction, nil if there is none
+func GetAccountLastTransactionBlockHeight(accountId int64) (*int64, error) {
+	var blockHeight *int64
+	err := DbConn.Table(accountTransactionTableName()+" account_transaction").
+		Select("MAX(account_transaction.block_height)").
+		Where("account_transaction.account_id = ?", accountId).
+		Row().
+		Scan(&blockHeight)
+	return blockHeight, err
+}
+
+// UpsertAccountTransactions inserts the new transactions and refreshes the block and the confirmations of the known ones
+func UpsertAccountTransactions(tx *gorm.DB, transactions []*entities.AccountTransaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	db := getDb(tx)
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}, {Name: "txid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block_height", "block_time", "amount_in", "amount_out", "fee", "confirmations", "updated_at"}),
+	}).Create(transactions).Error
+}
+
+// GetAccountTransactionsAndTotal returns the unconfirmed transactions first and then the newest blocks first
+func GetAccountTransactionsAndTotal(accountId int64, offset int, count int) ([]*entities.AccountTransaction, int64) {
+	var total int64
+	var transactions []*entities.AccountTransaction
+	query := getBaseAccountTransactionsQuery(accountId)
+	totalQuery := getBaseAccountTransactionsQuery(accountId)
+	query.
+		Order("account_transaction.block_height IS NULL DESC").
+		Order("account_transaction.block_height DESC").
+		Order("account_transaction.id DESC").
+		Limit(count).
+		Offset(offset).
+		Find(&transactions)
+	totalQuery.Count(&total)
+	return transactions, total
+}
+
+func getBaseAccountTransactionsQuery(accountId int64) *gorm.DB {
+	return DbConn.Table(accountTransactionTableName()+" account_transaction").
+		Where("account_transaction.account_id = ?", accountId)
+}
+
+///// Cron run queries
+
+// CronRunFilter holds the filters of the cron runs. Empty values are not applied
+type CronRunFilter struct {
+	Job         string
+	Status      string
+	TriggeredBy string
+}
+
+func CreateCronRun(tx *gorm.DB, run *entities.CronRun) error {
+	db := getDb(tx)
+	return db.Create(run).Error
+}
+
+func UpdateCronRun(tx *gorm.DB, run *entities.CronRun, updateData map[string]interface{}) error {
+	db := getDb(tx)
+	return db.Model(entities.CronRun{}).Where("id = ?", run.Id).Updates(updateData).Error
+}
+
+// InterruptCronRuns marks the running runs of the job as interrupted, only the holder of the job lock may call it
+func InterruptCronRuns(job string, finishedAt int64) (int64, error) {
+	result := DbConn.Model(entities.CronRun{}).
+		Where("job = ? AND status = ?", job, entities.CronRunStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      entities.CronRunStatusInterrupted,
+			"finished_at": finishedAt,
+			"duration_ms": gorm.Expr("(? - started_at) * 1000", finishedAt),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func GetCronRunById(id int64) *entities.CronRun {
+	var run *entities.CronRun
+	DbConn.Table(cronRunTableName()+" cron_run").
+		Where("cron_run.id = ?", id).
+		First(&run)
+	if run.Id == 0 {
+		return nil
+	}
+	return run
+}
+
+// GetCronRunsAndTotal returns the newest runs first
+func GetCronRunsAndTotal(filter CronRunFilter, offset int, count int) ([]*entities.CronRun, int64) {
+	var total int64
+	var runs []*entities.CronRun
+	query := getBaseCronRunQuery(filter)
+	totalQuery := getBaseCronRunQuery(filter)
+	query.
+		Order("cron_run.started_at DESC").
+		Order("cron_run.id DESC").
+		Limit(count).
+		Offset(offset).
+		Find(&runs)
+	totalQuery.Count(&total)
+	return runs, total
+}
+
+func getBaseCronRunQuery(filter CronRunFilter) *gorm.DB {
+	query := DbConn.Table(cronRunTableName() + " cron_run")
+	if filter.Job != "" {
+		query = query.Where("cron_run.job = ?", filter.Job)
+	}
+	if filter.Status != "" {
+		query = query.Where("cron_run.status = ?", filter.Status)
+	}
+	if filter.TriggeredBy != "" {
+		query = query.Where("cron_run.triggered_by = ?", filter.TriggeredBy)
+	}
+	return query
+}
+
+///// Api key queries
+
+// ApiKeyFilter the revoked keys are left out unless IncludeRevoked is set
+type ApiKeyFilter struct {
+	IncludeRevoked bool
+}
+
+func CreateApiKey(tx *gorm.DB, apiKey *entities.ApiKey) error {
+	db := getDb(tx)
+	return db.Create(apiKey).Error
+}
+
+func UpdateApiKey(tx *gorm.DB, apiKey *entities.ApiKey, updateData map[string]interface{}) error {
+	db := getDb(tx)
+	return db.Model(entities.ApiKey{}).Where("id = ?", apiKey.Id).Updates(updateData).Error
+}
+
+func GetApiKeyById(tx *gorm.DB, id int64) *entities.ApiKey {
+	db := getDb(tx)
+	var apiKey *entities.ApiKey
+	db.Table(apiKeyTableName()+" api_key").
+		Where("api_key.id = ?", id).
+		First(&apiKey)
+	if apiKey.Id == 0 {
+		return nil
+	}
+	return apiKey
+}
+
+// GetActiveApiKeysByPrefix returns the keys that are neither revoked nor expired, the caller compares the hashes
+func GetActiveApiKeysByPrefix(prefix string, now int64) []*entities.ApiKey {
+	var apiKeys []*entities.ApiKey
+	DbConn.Table(apiKeyTableName()+" api_key").
+		Where("api_key.prefix = ? AND api_key.revoked_at = 0", prefix).
+		Where("api_key.expires_at = 0 OR api_key.expires_at > ?", now).
+		Find(&apiKeys)
+	return apiKeys
+}
+
+// GetApiKeysAndTotal returns the newest keys first
+func GetApiKeysAndTotal(filter ApiKeyFilter, offset int, count int) ([]*entities.ApiKey, int64) {
+	var total int64
+	var apiKeys []*entities.ApiKey
+	query := getBaseApiKeyQuery(filter)
+	totalQuery := getBaseApiKeyQuery(filter)
+	query.
+		Order("api_key.id DESC").
+		Limit(count).
+		Offset(offset).
+		Find(&apiKeys)
+	totalQuery.Count(&total)
+	return apiKeys, total
+}
+
+func getBaseApiKeyQuery(filter ApiKeyFilter) *gorm.DB {
+	query := DbConn.Table(apiKeyTableName() + " api_key")
+	if !filter.IncludeRevoked {
+		query = query.Where("api_key.revoked_at = 0")
+	}
+	return query
+}
@@ -1,31 +1,112 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	errorHelper "go-gin-test-job/src/common/error-helpers"
 	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/logger"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"slices"
+	"sync"
 )
 
-func AdminApiKeyGuard() gin.HandlerFunc {
+// apiKeyLastUsedUpdateSec the last use of a key is written at most once in this time, not on every request
+const apiKeyLastUsedUpdateSec = 60
+
+// apiKeyUnknownCacheSec an unknown key is not looked up in the database again in this time
+const apiKeyUnknownCacheSec = 60
+
+// apiKeyUnknownCacheSize bounds the unknown keys kept in memory
+const apiKeyUnknownCacheSize = 10000
+
+// unknownApiKeys are the hashes of the unknown keys with the time they are kept until
+var unknownApiKeys = make(map[string]int64)
+var unknownApiKeysLock sync.Mutex
+
+// ApiKeyGuard lets in a key that has the scope, an unknown key is unauthorized and a key without the scope is forbidden
+func ApiKeyGuard(scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" || config.AppConfig.AdminXApiKey == "" || apiKey != config.AppConfig.AdminXApiKey {
+		scopes, found := getApiKeyScopes(apiKey)
+		if !found {
 			_ = errorHelper.RespondUnauthorizedError(c)
 			c.Abort()
 			return
 		}
+		if !slices.Contains(scopes, scope) {
+			_ = errorHelper.RespondForbiddenError(c, fmt.Sprintf("Api key does not have the %s scope", scope))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
 
-func CronApiKeyGuard() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" || config.AppConfig.CronXApiKey == "" || apiKey != config.AppConfig.CronXApiKey {
-			_ = errorHelper.RespondUnauthorizedError(c)
-			c.Abort()
-			return
+// getApiKeyScopes checks the keys of the environment first, then the keys of the database
+func getApiKeyScopes(apiKey string) ([]string, bool) {
+	if apiKey == "" {
+		return nil, false
+	}
+	apiKeyHash := entities.HashApiKey(apiKey)
+	if isStaticApiKey(apiKeyHash, config.AppConfig.AdminXApiKey) {
+		return entities.ApiKeyScopeList, true
+	}
+	if isStaticApiKey(apiKeyHash, config.AppConfig.CronXApiKey) {
+		return []string{entities.ApiKeyScopeCronRun, entities.ApiKeyScopeCronRead}, true
+	}
+	prefix := entities.GetApiKeyPrefix(apiKey)
+	now := timeUtil.GetUnixTime()
+	if prefix == "" || isUnknownApiKey(apiKeyHash, now) {
+		return nil, false
+	}
+	for _, storedApiKey := range database.GetActiveApiKeysByPrefix(prefix, now) {
+		if storedApiKey.Matches(apiKey) {
+			setApiKeyUsed(storedApiKey, now)
+			return storedApiKey.GetScopes(), true
 		}
-		c.Next()
+	}
+	setUnknownApiKey(apiKeyHash, now)
+	return nil, false
+}
+
+// isStaticApiKey compares the hashes in constant time, a key that is not set matches nothing
+func isStaticApiKey(apiKeyHash string, staticApiKey string) bool {
+	return staticApiKey != "" && subtle.ConstantTimeCompare([]byte(apiKeyHash), []byte(entities.HashApiKey(staticApiKey))) == 1
+}
+
+func isUnknownApiKey(apiKeyHash string, now int64) bool {
+	unknownApiKeysLock.Lock()
+	defer unknownApiKeysLock.Unlock()
+	return unknownApiKeys[apiKeyHash] > now
+}
+
+// setUnknownApiKey the expired keys are dropped when the cache is full, it is emptied if all of them are still kept
+func setUnknownApiKey(apiKeyHash string, now int64) {
+	unknownApiKeysLock.Lock()
+	defer unknownApiKeysLock.Unlock()
+	if len(unknownApiKeys) >= apiKeyUnknownCacheSize {
+		for hash, keptUntil := range unknownApiKeys {
+			if keptUntil <= now {
+				delete(unknownApiKeys, hash)
+			}
+		}
+		if len(unknownApiKeys) >= apiKeyUnknownCacheSize {
+			clear(unknownApiKeys)
+		}
+	}
+	unknownApiKeys[apiKeyHash] = now + apiKeyUnknownCacheSec
+}
+
+// setApiKeyUsed a failed write does not fail the request
+func setApiKeyUsed(apiKey *entities.ApiKey, now int64) {
+	if now-apiKey.LastUsedAt < apiKeyLastUsedUpdateSec {
+		return
+	}
+	if err := database.UpdateApiKey(nil, apiKey, apiKey.SetUsed(now)); err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Save api key %d last use error. %s", apiKey.Id, err.Error()))
 	}
 }
@@ -17,6 +17,8 @@ func ErrorHandler() gin.HandlerFunc {
 					c.JSON(http.StatusBadRequest, errorHelpers.NewResponseBadRequestErrorHTTP(err.Error()))
 				case http.StatusUnauthorized:
 					c.JSON(http.StatusUnauthorized, errorHelpers.NewResponseUnauthorizedErrorHTTP(err.Error()))
+				case http.StatusForbidden:
+					c.JSON(http.StatusForbidden, errorHelpers.NewResponseForbiddenErrorHTTP(err.Error()))
 				case http.StatusNotFound:
 					c.JSON(http.StatusNotFound, errorHelpers.NewResponseNotFoundErrorHTTP(err.Error()))
 				case http.StatusConflict:
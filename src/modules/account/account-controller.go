@@ -1,8 +1,12 @@
 package accountModule
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/logger"
 	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/src/modules/common/blockchain"
 	orderUtil "go-gin-test-job/src/utils/order"
 )
 
@@ -15,12 +19,18 @@ import (
 // @Param offset query int false "This is paging offset. 0 by default" minimum(0) default(0)
 // @Param count query int false "Max item count in single response. 100 by default" minimum(1) maximum(100) default(100)
 // @Param status query string false "Account statuses: On, Off" Enums("On", "Off") default("On")
-// @Param orderBy query string false "Comma-separated sort order options (sort fields: id, updated_at, address, name, rank, sort order: ASC,DESC)" default(id ASC)
+// @Param orderBy query string false "Comma-separated sort order options (sort fields: id, updated_at, created_at, address, name, rank, balance, sort order: ASC,DESC)" default(id ASC)
 // @Param search query string false "Search term for address, name, and memo fields"
-// @Param X-API-Key header string true "Admin api key"
+// @Param searchMode query string false "Search mode: contains matches any part of address, name and memo; prefix matches the start of address and name; fulltext matches words of name and memo and the start of address, sorted by relevance when orderBy is empty" Enums("contains", "prefix", "fulltext") default("contains")
+// @Param includeDeleted query bool false "Include archived accounts. false by default" default(false)
+// @Param tagFilter query accountModuleDto.AccountTagFilterDto false "Tags filter"
+// @Param rangeFilter query accountModuleDto.AccountRangeFilterDto false "Range filters"
+// @Param cursor query string false "Opaque cursor from nextCursor of the previous page. Enables keyset pagination, offset must be 0. The page is a GetAccountCursorResponseDto without offset and total"
+// @Param X-API-Key header string true "Api key with the account:read scope"
 // @Success 200 {object} accountModuleDto.GetAccountResponseDto
 // @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
 // @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
 // @Router /account [get]
 func GetAccounts(c *gin.Context) {
 	dto, err := accountModuleDto.CreateGetAccountRequestDto(c)
@@ -31,8 +41,168 @@ func GetAccounts(c *gin.Context) {
 	if err != nil {
 		return
 	}
-	accounts, total := getAccounts(dto.Status, orderParams, dto.Offset, dto.Count, dto.Search)
-	c.JSON(200, accountModuleDto.CreateGetAccountResponseDto(dto.Offset, dto.Count, total, accounts))
+	filter := getAccountFilter(dto.Status, dto.Search, dto.SearchMode, dto.IncludeDeleted, dto.AccountRangeFilterDto, dto.AccountTagFilterDto)
+	if dto.Cursor != "" {
+		accounts, nextCursor, err := getAccountsByCursor(c, filter, orderParams, dto.Cursor, dto.Count)
+		if err != nil {
+			return
+		}
+		c.JSON(200, accountModuleDto.CreateGetAccountCursorResponseDto(dto.Count, accounts, nextCursor))
+		return
+	}
+	accounts, total, nextCursor := getAccounts(filter, orderParams, dto.Offset, dto.Count)
+	c.JSON(200, accountModuleDto.CreateGetAccountResponseDto(dto.Offset, dto.Count, total, accounts, nextCursor))
+}
+
+// GetAccountStats Get account statistics
+// @Summary Get account statistics
+// @Description Get count and total balance per status, rank histogram, top accounts by balance and the oldest updated_at among On accounts. Accepts the same filters as the list of accounts
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param top query int false "Count of top accounts by balance. 10 by default" minimum(1) maximum(100) default(10)
+// @Param rankBucketSize query int false "Width of the rank histogram bucket. 10 by default" minimum(1) maximum(100) default(10)
+// @Param status query string false "Account statuses: On, Off" Enums("On", "Off")
+// @Param search query string false "Search term for address, name, and memo fields"
+// @Param searchMode query string false "Search mode: contains, prefix or fulltext" Enums("contains", "prefix", "fulltext") default("contains")
+// @Param includeDeleted query bool false "Include archived accounts. false by default" default(false)
+// @Param tagFilter query accountModuleDto.AccountTagFilterDto false "Tags filter"
+// @Param rangeFilter query accountModuleDto.AccountRangeFilterDto false "Range filters"
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.GetAccountStatsResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Router /account/stats [get]
+func GetAccountStats(c *gin.Context) {
+	dto, err := accountModuleDto.CreateGetAccountStatsRequestDto(c)
+	if err != nil {
+		return
+	}
+	filter := getAccountFilter(dto.Status, dto.Search, dto.SearchMode, dto.IncludeDeleted, dto.AccountRangeFilterDto, dto.AccountTagFilterDto)
+	c.JSON(200, getAccountStats(filter, dto.RankBucketSize, dto.Top))
+}
+
+// ExportAccounts Export accounts
+// @Summary Export accounts
+// @Description Export accounts as a CSV file. Accepts the same filters as the list of accounts
+// @Tags Account
+// @Accept json
+// @Produce text/csv
+// @Param format query string false "Export format" Enums("csv") default("csv")
+// @Param status query string false "Account statuses: On, Off" Enums("On", "Off")
+// @Param orderBy query string false "Comma-separated sort order options (sort fields: id, updated_at, created_at, address, name, rank, balance, sort order: ASC,DESC)" default(id ASC)
+// @Param search query string false "Search term for address, name, and memo fields"
+// @Param searchMode query string false "Search mode: contains matches any part of address, name and memo; prefix matches the start of address and name; fulltext matches words of name and memo and the start of address, sorted by relevance when orderBy is empty" Enums("contains", "prefix", "fulltext") default("contains")
+// @Param includeDeleted query bool false "Include archived accounts. false by default" default(false)
+// @Param tagFilter query accountModuleDto.AccountTagFilterDto false "Tags filter"
+// @Param rangeFilter query accountModuleDto.AccountRangeFilterDto false "Range filters"
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {file} file
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Router /account/export [get]
+func ExportAccounts(c *gin.Context) {
+	dto, err := accountModuleDto.CreateGetExportAccountRequestDto(c)
+	if err != nil {
+		return
+	}
+	orderParams, err := orderUtil.GetOrderByParamsSecure(c, dto.OrderBy, ",", accountModuleDto.GetAvailableAccountSortFieldList)
+	if err != nil {
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=accounts.csv")
+	c.Status(200)
+	filter := getAccountFilter(dto.Status, dto.Search, dto.SearchMode, dto.IncludeDeleted, dto.AccountRangeFilterDto, dto.AccountTagFilterDto)
+	if err := exportAccountsCsv(c.Writer, filter, orderParams); err != nil {
+		// Headers are already sent, so the error can only be logged
+		logger.Logger.Error().Msg(fmt.Sprintf("Export accounts error. %s", err.Error()))
+	}
+}
+
+// ImportAccounts Import accounts
+// @Summary Import accounts
+// @Description Import accounts from a CSV file with the header address,name,rank,memo,status. Every row is validated and created on its own, accepted and rejected rows are reported with their line numbers
+// @Tags Account
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file"
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Success 200 {object} accountModuleDto.PostImportAccountResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Router /account/import [post]
+func ImportAccounts(c *gin.Context) {
+	dto, err := accountModuleDto.CreatePostImportAccountRequestDto(c)
+	if err != nil {
+		return
+	}
+	file, err := dto.File.Open()
+	if err != nil {
+		_ = errorHelpers.RespondBadRequestError(c, "File cannot be read")
+		return
+	}
+	defer file.Close()
+	result, err := importAccountsCsv(c, file)
+	if err != nil {
+		return
+	}
+	c.JSON(200, result)
+}
+
+// GetAccountById Get account by id
+// @Summary Get account by id
+// @Description Get account by id
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.AccountDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id} [get]
+func GetAccountById(c *gin.Context) {
+	dto, err := accountModuleDto.CreateAccountIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	account, err := getAccountById(c, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateGetAccountByIdResponseDto(account))
+}
+
+// GetAccountByAddress Get account by address
+// @Summary Get account by address
+// @Description Get account by address
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param address path string true "Account address"
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.AccountDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/by-address/{address} [get]
+func GetAccountByAddress(c *gin.Context) {
+	dto, err := accountModuleDto.CreateGetAccountByAddressRequestDto(c)
+	if err != nil {
+		return
+	}
+	account, err := getAccountByAddress(c, dto.Address)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateGetAccountByAddressResponseDto(account))
 }
 
 // CreateAccount Create new account
@@ -41,11 +211,12 @@ func GetAccounts(c *gin.Context) {
 // @Tags Account
 // @Accept json
 // @Produce json
-// @Param X-API-Key header string true "Admin api key"
+// @Param X-API-Key header string true "Api key with the account:write scope"
 // @Param request body accountModuleDto.PostCreateAccountRequestDto true "Request body"
 // @Success 200 {object} accountModuleDto.AccountDto
 // @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
 // @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
 // @Failure 409 {object} errorHelpers.ResponseConflictErrorHTTP{}
 // @Router /account [post]
 func CreateAccount(c *gin.Context) {
@@ -59,3 +230,305 @@ func CreateAccount(c *gin.Context) {
 	}
 	c.JSON(200, accountModuleDto.CreatePostCreateAccountResponseDto(account))
 }
+
+// BulkCreateAccounts Create accounts in bulk
+// @Summary Create accounts in bulk
+// @Description Create up to 500 accounts and return a result for each item. In atomic mode nothing is created if any item fails, in bestEffort mode every valid item is created
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Param request body accountModuleDto.PostBulkCreateAccountRequestDto true "Request body"
+// @Success 200 {object} accountModuleDto.PostBulkCreateAccountResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 500 {object} errorHelpers.ResponseInternalErrorHTTP{}
+// @Router /account/bulk [post]
+func BulkCreateAccounts(c *gin.Context) {
+	dto, err := accountModuleDto.CreatePostBulkCreateAccountRequestDto(c)
+	if err != nil {
+		return
+	}
+	results, err := bulkCreateAccounts(c, dto.Mode, dto.Items)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreatePostBulkCreateAccountResponseDto(dto.Mode, results))
+}
+
+// PatchAccount Update account
+// @Summary Update account
+// @Description Update name, rank, memo, status or refresh interval of an account. Only the sent fields are changed, address cannot be changed. An account turned on again starts without refresh failures
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Param request body accountModuleDto.PatchAccountRequestDto true "Request body"
+// @Success 200 {object} accountModuleDto.AccountDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id} [patch]
+func PatchAccount(c *gin.Context) {
+	dto, err := accountModuleDto.CreatePatchAccountRequestDto(c)
+	if err != nil {
+		return
+	}
+	account, err := patchAccount(c, dto.Id, dto.Name, dto.Rank, dto.Memo, dto.Status, dto.RefreshIntervalSec)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreatePatchAccountResponseDto(account))
+}
+
+// DeleteAccount Archive account
+// @Summary Archive account
+// @Description Soft delete an account. The account keeps its history and its address can be registered again
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Success 200 {object} accountModuleDto.AccountDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id} [delete]
+func DeleteAccount(c *gin.Context) {
+	dto, err := accountModuleDto.CreateAccountIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	account, err := deleteAccount(c, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateDeleteAccountResponseDto(account))
+}
+
+// RestoreAccount Restore archived account
+// @Summary Restore archived account
+// @Description Restore an archived account if its address is not used by another account
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Success 200 {object} accountModuleDto.AccountDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Failure 409 {object} errorHelpers.ResponseConflictErrorHTTP{}
+// @Router /account/{id}/restore [post]
+func RestoreAccount(c *gin.Context) {
+	dto, err := accountModuleDto.CreateAccountIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	account, err := restoreAccount(c, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreatePostRestoreAccountResponseDto(account))
+}
+
+// GetAccountTags Get account tags
+// @Summary Get account tags
+// @Description Get the tags of an account sorted by name
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.AccountTagsResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id}/tags [get]
+func GetAccountTags(c *gin.Context) {
+	dto, err := accountModuleDto.CreateAccountIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	tags, err := getAccountTags(c, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateAccountTagsResponseDto(dto.Id, tags))
+}
+
+// AddAccountTags Add account tags
+// @Summary Add account tags
+// @Description Add tags to an account. Tags are trimmed and lowercased, unknown tags are created and the tags the account already has are skipped
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Param request body accountModuleDto.PostAccountTagsRequestDto true "Request body"
+// @Success 200 {object} accountModuleDto.AccountTagsResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id}/tags [post]
+func AddAccountTags(c *gin.Context) {
+	dto, err := accountModuleDto.CreatePostAccountTagsRequestDto(c)
+	if err != nil {
+		return
+	}
+	tags, err := addAccountTags(c, dto.Id, dto.GetTagList())
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateAccountTagsResponseDto(dto.Id, tags))
+}
+
+// RemoveAccountTag Remove account tag
+// @Summary Remove account tag
+// @Description Remove a tag from an account and return the remaining tags
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param tag path string true "Tag"
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Success 200 {object} accountModuleDto.AccountTagsResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id}/tags/{tag} [delete]
+func RemoveAccountTag(c *gin.Context) {
+	dto, err := accountModuleDto.CreateDeleteAccountTagRequestDto(c)
+	if err != nil {
+		return
+	}
+	tags, err := removeAccountTag(c, dto.Id, dto.GetTagName())
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateAccountTagsResponseDto(dto.Id, tags))
+}
+
+// GetAccountBalanceHistory Get account balance history
+// @Summary Get account balance history
+// @Description Get the balance snapshots of an account written by every balance update, newest first
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param offset query int false "This is paging offset. 0 by default" minimum(0) default(0)
+// @Param count query int false "Max item count in single response. 100 by default" minimum(1) maximum(100) default(100)
+// @Param from query int false "Taken at or after, unix seconds" minimum(0)
+// @Param to query int false "Taken at or before, unix seconds" minimum(0)
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.GetAccountBalanceHistoryResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id}/balance-history [get]
+func GetAccountBalanceHistory(c *gin.Context) {
+	dto, err := accountModuleDto.CreateGetAccountBalanceHistoryRequestDto(c)
+	if err != nil {
+		return
+	}
+	histories, total, err := getAccountBalanceHistory(c, dto.Id, dto.From, dto.To, dto.Offset, dto.Count)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateGetAccountBalanceHistoryResponseDto(dto.Offset, dto.Count, total, histories))
+}
+
+// GetAccountTransactions Get account transactions
+// @Summary Get account transactions
+// @Description Get the blockchain transactions of an account pulled by the cron, unconfirmed first and then the newest blocks first
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param offset query int false "This is paging offset. 0 by default" minimum(0) default(0)
+// @Param count query int false "Max item count in single response. 100 by default" minimum(1) maximum(100) default(100)
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.GetAccountTransactionsResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /account/{id}/transactions [get]
+func GetAccountTransactions(c *gin.Context) {
+	dto, err := accountModuleDto.CreateGetAccountTransactionsRequestDto(c)
+	if err != nil {
+		return
+	}
+	transactions, total, err := getAccountTransactions(c, dto.Id, dto.Offset, dto.Count)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateGetAccountTransactionsResponseDto(dto.Offset, dto.Count, total, transactions))
+}
+
+// GetAccountUtxos Get account utxos
+// @Summary Get account utxos
+// @Description Get the unspent outputs of the account address from the blockchain api
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.GetAccountUtxosResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Failure 502 {object} errorHelpers.ResponseBadGatewayErrorHTTP{}
+// @Router /account/{id}/utxos [get]
+func GetAccountUtxos(c *gin.Context) {
+	dto, err := accountModuleDto.CreateAccountIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	account, utxos, err := getAccountUtxos(c, blockchain.Resolver, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, accountModuleDto.CreateGetAccountUtxosResponseDto(account, utxos))
+}
+
+// GetAccountsUtxos Get utxos of accounts
+// @Summary Get utxos of accounts
+// @Description Get the unspent outputs of a page of accounts ordered by id. Accepts the same filters as the list of accounts. The accounts whose outputs can not be read are listed in failed
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param offset query int false "This is paging offset of the accounts. 0 by default" minimum(0) default(0)
+// @Param count query int false "Max account count in single response. 20 by default" minimum(1) maximum(100) default(20)
+// @Param dustThreshold query string false "Outputs below this value are dust and are left out, decimal string"
+// @Param dustOnly query bool false "Keep only the dust outputs, requires dustThreshold. false by default" default(false)
+// @Param status query string false "Account statuses: On, Off" Enums("On", "Off")
+// @Param search query string false "Search term for address, name, and memo fields"
+// @Param searchMode query string false "Search mode: contains, prefix or fulltext" Enums("contains", "prefix", "fulltext") default("contains")
+// @Param includeDeleted query bool false "Include archived accounts. false by default" default(false)
+// @Param tagFilter query accountModuleDto.AccountTagFilterDto false "Tags filter"
+// @Param rangeFilter query accountModuleDto.AccountRangeFilterDto false "Range filters"
+// @Param X-API-Key header string true "Api key with the account:read scope"
+// @Success 200 {object} accountModuleDto.GetAccountsUtxosResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Router /account/utxos [get]
+func GetAccountsUtxos(c *gin.Context) {
+	dto, err := accountModuleDto.CreateGetAccountsUtxosRequestDto(c)
+	if err != nil {
+		return
+	}
+	filter := getAccountFilter(dto.Status, dto.Search, dto.SearchMode, dto.IncludeDeleted, dto.AccountRangeFilterDto, dto.AccountTagFilterDto)
+	c.JSON(200, getAccountsUtxos(c.Request.Context(), blockchain.Resolver, filter, dto.GetDustThreshold(), dto.DustOnly, dto.Offset, dto.Count))
+}
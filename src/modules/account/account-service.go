@@ -1,15 +1,210 @@
 package accountModule
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
 	"go-gin-test-job/src/database"
 	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/logger"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cursorUtil "go-gin-test-job/src/utils/cursor"
+	orderUtil "go-gin-test-job/src/utils/order"
 	"gorm.io/gorm"
+	"io"
+	"strconv"
+	"strings"
 )
 
-func getAccounts(status entities.AccountStatus, orderParams map[string]string, offset int, count int, search string) ([]*entities.Account, int64) {
-	return database.GetAccountsAndTotal(status, orderParams, offset, count, search)
+var errBulkCreateAccountRollback = errors.New("Bulk create account rollback")
+
+func getAccountFilter(status entities.AccountStatus, search string, searchMode string, includeDeleted bool, rangeFilter accountModuleDto.AccountRangeFilterDto, tagFilter accountModuleDto.AccountTagFilterDto) database.AccountFilter {
+	accountSearchMode := entities.AccountSearchMode(searchMode)
+	// Without FULLTEXT index support the fulltext mode falls back to the plain LIKE search
+	if accountSearchMode == entities.AccountSearchModeFulltext && !config.AppConfig.SearchFulltextEnabled {
+		accountSearchMode = entities.AccountSearchModeContains
+	}
+	return database.AccountFilter{
+		Status:         status,
+		Search:         search,
+		SearchMode:     accountSearchMode,
+		IncludeDeleted: includeDeleted,
+		Tags:           tagFilter.GetTagList(),
+		TagsMode:       tagFilter.GetTagsMode(),
+		RankMin:        rangeFilter.RankMin,
+		RankMax:        rangeFilter.RankMax,
+		BalanceMin:     rangeFilter.GetBalanceMin(),
+		BalanceMax:     rangeFilter.GetBalanceMax(),
+		CreatedFrom:    rangeFilter.CreatedFrom,
+		CreatedTo:      rangeFilter.CreatedTo,
+		UpdatedFrom:    rangeFilter.UpdatedFrom,
+		UpdatedTo:      rangeFilter.UpdatedTo,
+		PendingFunds:   rangeFilter.PendingFunds,
+	}
+}
+
+func getAccounts(filter database.AccountFilter, orderParams []orderUtil.OrderParam, offset int, count int) ([]*entities.Account, int64, *string) {
+	accounts, total := database.GetAccountsAndTotal(filter, orderParams, offset, count)
+	var nextCursor *string
+	// Relevance is not a column, so the rows sorted by it cannot be continued by a cursor
+	if len(accounts) > 0 && int64(offset+len(accounts)) < total && !database.IsAccountsRelevanceOrder(filter, orderParams) {
+		nextCursor = getAccountsNextCursor(orderParams, accounts[len(accounts)-1])
+	}
+	return accounts, total, nextCursor
+}
+
+func getAccountsByCursor(c *gin.Context, filter database.AccountFilter, orderParams []orderUtil.OrderParam, cursor string, count int) ([]*entities.Account, *string, error) {
+	if database.IsAccountsRelevanceOrder(filter, orderParams) {
+		return nil, nil, errorHelpers.RespondBadRequestError(c, "Cursor requires orderBy in fulltext search mode")
+	}
+	signature, cursorValues, err := cursorUtil.Decode(cursor)
+	if err != nil {
+		return nil, nil, errorHelpers.RespondBadRequestError(c, "Cursor is invalid")
+	}
+	fullOrderParams := database.WithAccountsOrderTiebreaker(orderParams)
+	if signature != getAccountsCursorSignature(fullOrderParams) || len(cursorValues) != len(fullOrderParams) {
+		return nil, nil, errorHelpers.RespondBadRequestError(c, "Cursor does not match orderBy")
+	}
+	// One extra row tells whether there is a next page
+	accounts := database.GetAccountsAfterCursor(filter, orderParams, cursorValues, count+1)
+	var nextCursor *string
+	if len(accounts) > count {
+		accounts = accounts[:count]
+		nextCursor = getAccountsNextCursor(orderParams, accounts[len(accounts)-1])
+	}
+	return accounts, nextCursor, nil
+}
+
+func getAccountsNextCursor(orderParams []orderUtil.OrderParam, lastAccount *entities.Account) *string {
+	fullOrderParams := database.WithAccountsOrderTiebreaker(orderParams)
+	values := make([]string, 0, len(fullOrderParams))
+	for _, orderParam := range fullOrderParams {
+		values = append(values, getAccountOrderValue(lastAccount, orderParam.Field))
+	}
+	cursor := cursorUtil.Encode(getAccountsCursorSignature(fullOrderParams), values)
+	return &cursor
+}
+
+func getAccountsCursorSignature(orderParams []orderUtil.OrderParam) string {
+	parts := make([]string, 0, len(orderParams))
+	for _, orderParam := range orderParams {
+		parts = append(parts, orderParam.Field+" "+orderParam.Direction)
+	}
+	return strings.Join(parts, ",")
+}
+
+func getAccountOrderValue(account *entities.Account, field string) string {
+	switch field {
+	case "id":
+		return strconv.FormatInt(account.Id, 10)
+	case "updated_at":
+		return strconv.FormatInt(account.UpdatedAt, 10)
+	case "address":
+		return account.Address
+	case "name":
+		return account.Name
+	case "rank":
+		return strconv.Itoa(int(account.Rank))
+	case "balance":
+		return account.Balance.String()
+	case "created_at":
+		return strconv.FormatInt(account.CreatedAt, 10)
+	}
+	return ""
+}
+
+func getAccountStats(filter database.AccountFilter, rankBucketSize int, top int) accountModuleDto.GetAccountStatsResponseDto {
+	statusStats := database.GetAccountsStatusStats(filter)
+	rankBuckets := database.GetAccountsRankHistogram(filter, rankBucketSize)
+	topAccounts := database.GetTopAccountsByBalance(filter, top)
+	oldestUpdatedAt := database.GetOldestAccountUpdatedAt(filter)
+	return accountModuleDto.CreateGetAccountStatsResponseDto(statusStats, rankBuckets, rankBucketSize, topAccounts, oldestUpdatedAt)
+}
+
+func getAccountById(c *gin.Context, id int64) (*entities.Account, error) {
+	account := database.GetAccountById(nil, id)
+	if account == nil {
+		return nil, errorHelpers.RespondNotFoundError(c, "Account not found")
+	}
+	return account, nil
+}
+
+func getAccountByAddress(c *gin.Context, address string) (*entities.Account, error) {
+	account := database.GetAccountByAddress(address)
+	if account == nil {
+		return nil, errorHelpers.RespondNotFoundError(c, "Account not found")
+	}
+	return account, nil
+}
+
+func exportAccountsCsv(writer io.Writer, filter database.AccountFilter, orderParams []orderUtil.OrderParam) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(accountModuleDto.AccountCsvColumns); err != nil {
+		return err
+	}
+	err := database.IterateAccounts(filter, orderParams, func(account *entities.Account) error {
+		if err := csvWriter.Write(accountModuleDto.CreateAccountCsvRow(account)); err != nil {
+			return err
+		}
+		// Flush every row so the client receives the file as it is read from the database
+		csvWriter.Flush()
+		return csvWriter.Error()
+	})
+	if err != nil {
+		return err
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func importAccountsCsv(c *gin.Context, reader io.Reader) (*accountModuleDto.PostImportAccountResponseDto, error) {
+	result := accountModuleDto.NewPostImportAccountResponseDto()
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errorHelpers.RespondBadRequestError(c, "CSV header is missing")
+	}
+	indexes, errorMessage := accountModuleDto.GetAccountCsvImportColumnIndexes(header)
+	if errorMessage != "" {
+		return nil, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// A malformed row is rejected, any other read error stops the import
+			var parseError *csv.ParseError
+			if !errors.As(err, &parseError) {
+				return nil, errorHelpers.RespondBadRequestError(c, "File cannot be read")
+			}
+			result.AddRejected(parseError.StartLine, "", parseError.Err.Error())
+			continue
+		}
+		line, _ := csvReader.FieldPos(0)
+		dto, errorMessage := accountModuleDto.CreatePostCreateAccountRequestDtoFromCsvRow(indexes, record)
+		if errorMessage != "" {
+			result.AddRejected(line, dto.Address, errorMessage)
+			continue
+		}
+		// The gin context is not passed so that a conflict does not end the request
+		account, err := createAccount(nil, dto.Address, dto.Name, dto.Rank, dto.Memo, dto.Status)
+		if err != nil {
+			result.AddRejected(line, dto.Address, err.Error())
+			continue
+		}
+		result.AddAccepted(line, account)
+	}
+	return result, nil
 }
 
 func createAccount(c *gin.Context, address string, name string, rank int8, memo *string, status entities.AccountStatus) (*entities.Account, error) {
@@ -30,3 +225,243 @@ func createAccount(c *gin.Context, address string, name string, rank int8, memo
 	}
 	return account, nil
 }
+
+func bulkCreateAccounts(c *gin.Context, mode accountModuleDto.BulkCreateAccountMode, items []accountModuleDto.PostCreateAccountRequestDto) ([]accountModuleDto.BulkCreateAccountItemResultDto, error) {
+	results := make([]accountModuleDto.BulkCreateAccountItemResultDto, 0, len(items))
+	if mode == accountModuleDto.BulkCreateAccountModeBestEffort {
+		// Every item is created in its own transaction
+		for index, item := range items {
+			var result accountModuleDto.BulkCreateAccountItemResultDto
+			_ = database.DbConn.Transaction(func(tx *gorm.DB) error {
+				result = createBulkAccountItem(tx, index, item)
+				return nil
+			}, database.DefaultTxOptions)
+			results = append(results, result)
+		}
+		return results, nil
+	}
+	// All items are created in one transaction which is rolled back if any item fails
+	hasFailures := false
+	transactionError := database.DbConn.Transaction(func(tx *gorm.DB) error {
+		for index, item := range items {
+			result := createBulkAccountItem(tx, index, item)
+			if result.Status != accountModuleDto.BulkCreateAccountItemStatusCreated {
+				hasFailures = true
+			}
+			results = append(results, result)
+		}
+		if hasFailures {
+			return errBulkCreateAccountRollback
+		}
+		return nil
+	}, database.DefaultTxOptions)
+	if transactionError != nil && !hasFailures {
+		return nil, errorHelpers.RespondInternalError(c, transactionError.Error())
+	}
+	if hasFailures {
+		for index, result := range results {
+			if result.Status == accountModuleDto.BulkCreateAccountItemStatusCreated {
+				results[index] = accountModuleDto.CreateBulkCreateAccountItemResultDto(result.Index, result.Address, accountModuleDto.BulkCreateAccountItemStatusRolledBack, "", nil)
+			}
+		}
+	}
+	return results, nil
+}
+
+func createBulkAccountItem(tx *gorm.DB, index int, item accountModuleDto.PostCreateAccountRequestDto) accountModuleDto.BulkCreateAccountItemResultDto {
+	if errorMessage := accountModuleDto.GetPostCreateAccountRequestDtoErrorMessage(&item); errorMessage != "" {
+		return accountModuleDto.CreateBulkCreateAccountItemResultDto(index, item.Address, accountModuleDto.BulkCreateAccountItemStatusValidationError, errorMessage, nil)
+	}
+	if database.IsAddressExists(tx, item.Address) {
+		return accountModuleDto.CreateBulkCreateAccountItemResultDto(index, item.Address, accountModuleDto.BulkCreateAccountItemStatusConflict, "Address already exists", nil)
+	}
+	account, err := database.CreateAccount(tx, entities.CreateAccount(item.Address, item.Name, item.Rank, item.Memo, item.Status))
+	if err != nil {
+		return accountModuleDto.CreateBulkCreateAccountItemResultDto(index, item.Address, accountModuleDto.BulkCreateAccountItemStatusError, err.Error(), nil)
+	}
+	return accountModuleDto.CreateBulkCreateAccountItemResultDto(index, item.Address, accountModuleDto.BulkCreateAccountItemStatusCreated, "", account)
+}
+
+func patchAccount(c *gin.Context, id int64, name *string, rank *int8, memo *string, status *entities.AccountStatus, refreshIntervalSec *int) (*entities.Account, error) {
+	var account *entities.Account
+	transactionError := database.DbConn.Transaction(func(tx *gorm.DB) error {
+		existingAccount := database.GetAccountById(tx, id)
+		if existingAccount == nil {
+			return errorHelpers.RespondNotFoundError(c, "Account not found")
+		}
+		updateData := existingAccount.UpdateDetails(name, rank, memo, status, refreshIntervalSec)
+		if err := database.UpdateAccount(tx, existingAccount, updateData); err != nil {
+			return err
+		}
+		account = existingAccount
+		return nil
+	}, database.DefaultTxOptions)
+	if transactionError != nil {
+		return nil, transactionError
+	}
+	return account, nil
+}
+
+func deleteAccount(c *gin.Context, id int64) (*entities.Account, error) {
+	var account *entities.Account
+	transactionError := database.DbConn.Transaction(func(tx *gorm.DB) error {
+		existingAccount := database.GetAccountById(tx, id)
+		if existingAccount == nil {
+			return errorHelpers.RespondNotFoundError(c, "Account not found")
+		}
+		updateData := existingAccount.Archive()
+		if err := database.UpdateAccount(tx, existingAccount, updateData); err != nil {
+			return err
+		}
+		account = existingAccount
+		return nil
+	}, database.DefaultTxOptions)
+	if transactionError != nil {
+		return nil, transactionError
+	}
+	return account, nil
+}
+
+func restoreAccount(c *gin.Context, id int64) (*entities.Account, error) {
+	var account *entities.Account
+	transactionError := database.DbConn.Transaction(func(tx *gorm.DB) error {
+		existingAccount := database.GetAccountByIdIncludeDeleted(tx, id)
+		if existingAccount == nil {
+			return errorHelpers.RespondNotFoundError(c, "Account not found")
+		}
+		if !existingAccount.IsDeleted() {
+			return errorHelpers.RespondConflictError(c, "Account is not archived")
+		}
+		if database.IsAddressExists(tx, existingAccount.Address) {
+			return errorHelpers.RespondConflictError(c, "Address already exists")
+		}
+		updateData := existingAccount.Restore()
+		if err := database.UpdateAccount(tx, existingAccount, updateData); err != nil {
+			return err
+		}
+		account = existingAccount
+		return nil
+	}, database.DefaultTxOptions)
+	if transactionError != nil {
+		return nil, transactionError
+	}
+	return account, nil
+}
+
+func getAccountTags(c *gin.Context, id int64) ([]*entities.AccountTag, error) {
+	if _, err := getAccountById(c, id); err != nil {
+		return nil, err
+	}
+	return database.GetAccountTags(nil, id), nil
+}
+
+func addAccountTags(c *gin.Context, id int64, names []string) ([]*entities.AccountTag, error) {
+	var tags []*entities.AccountTag
+	transactionError := database.DbConn.Transaction(func(tx *gorm.DB) error {
+		existingAccount := database.GetAccountById(tx, id)
+		if existingAccount == nil {
+			return errorHelpers.RespondNotFoundError(c, "Account not found")
+		}
+		newTags, err := database.GetOrCreateAccountTags(tx, names)
+		if err != nil {
+			return err
+		}
+		if err := database.AddAccountTags(tx, existingAccount.Id, newTags); err != nil {
+			return err
+		}
+		tags = database.GetAccountTags(tx, existingAccount.Id)
+		return nil
+	}, database.DefaultTxOptions)
+	if transactionError != nil {
+		return nil, transactionError
+	}
+	return tags, nil
+}
+
+func removeAccountTag(c *gin.Context, id int64, name string) ([]*entities.AccountTag, error) {
+	var tags []*entities.AccountTag
+	transactionError := database.DbConn.Transaction(func(tx *gorm.DB) error {
+		existingAccount := database.GetAccountById(tx, id)
+		if existingAccount == nil {
+			return errorHelpers.RespondNotFoundError(c, "Account not found")
+		}
+		tag := database.GetAccountTagByName(tx, name)
+		if tag == nil {
+			return errorHelpers.RespondNotFoundError(c, "Tag not found")
+		}
+		removed, err := database.RemoveAccountTag(tx, existingAccount.Id, tag.Id)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return errorHelpers.RespondNotFoundError(c, "Tag not found")
+		}
+		tags = database.GetAccountTags(tx, existingAccount.Id)
+		return nil
+	}, database.DefaultTxOptions)
+	if transactionError != nil {
+		return nil, transactionError
+	}
+	return tags, nil
+}
+
+func getAccountBalanceHistory(c *gin.Context, id int64, from *int64, to *int64, offset int, count int) ([]*entities.AccountBalanceHistory, int64, error) {
+	if _, err := getAccountById(c, id); err != nil {
+		return nil, 0, err
+	}
+	filter := database.AccountBalanceHistoryFilter{AccountId: id, CreatedFrom: from, CreatedTo: to}
+	histories, total := database.GetAccountBalanceHistoryAndTotal(filter, offset, count)
+	return histories, total, nil
+}
+
+func getAccountTransactions(c *gin.Context, id int64, offset int, count int) ([]*entities.AccountTransaction, int64, error) {
+	if _, err := getAccountById(c, id); err != nil {
+		return nil, 0, err
+	}
+	transactions, total := database.GetAccountTransactionsAndTotal(id, offset, count)
+	return transactions, total, nil
+}
+
+func getAccountUtxos(c *gin.Context, resolver *blockchain.BalanceResolver, id int64) (*entities.Account, []blockchain.AddressUtxo, error) {
+	account, err := getAccountById(c, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	utxos, err := resolver.GetAddressUtxos(c.Request.Context(), account.Address)
+	if err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Get account %d address %s utxos error. %s", account.Id, account.Address, err.Error()))
+		return nil, nil, errorHelpers.RespondBadGatewayError(c, "Utxos are not available")
+	}
+	return account, utxos, nil
+}
+
+// getAccountsUtxos reads the outputs of a page of the filtered accounts, an account that fails is reported and skipped
+func getAccountsUtxos(ctx context.Context, resolver *blockchain.BalanceResolver, filter database.AccountFilter, dustThreshold *decimal.Decimal, dustOnly bool, offset int, count int) *accountModuleDto.GetAccountsUtxosResponseDto {
+	orderParams := []orderUtil.OrderParam{{Field: "id", Direction: "ASC"}}
+	accounts, total := database.GetAccountsAndTotal(filter, orderParams, offset, count)
+	result := accountModuleDto.NewGetAccountsUtxosResponseDto(offset, count, total)
+	for _, account := range accounts {
+		utxos, err := resolver.GetAddressUtxos(ctx, account.Address)
+		if err != nil {
+			logger.Logger.Error().Msg(fmt.Sprintf("Get account %d address %s utxos error. %s", account.Id, account.Address, err.Error()))
+			result.AddFailed(account, err)
+			continue
+		}
+		result.AddUtxos(account, filterUtxosByDust(utxos, dustThreshold, dustOnly))
+	}
+	return result
+}
+
+// filterUtxosByDust leaves out the outputs below the threshold, or keeps only them with dustOnly
+func filterUtxosByDust(utxos []blockchain.AddressUtxo, dustThreshold *decimal.Decimal, dustOnly bool) []blockchain.AddressUtxo {
+	if dustThreshold == nil {
+		return utxos
+	}
+	filtered := make([]blockchain.AddressUtxo, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.Value.LessThan(*dustThreshold) == dustOnly {
+			filtered = append(filtered, utxo)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,80 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"go-gin-test-job/src/database/entities"
+	"strconv"
+	"strings"
+)
+
+// AccountCsvColumns is the header of the exported CSV file. New columns go to the end so that the existing ones keep their position
+var AccountCsvColumns = []string{"id", "address", "name", "rank", "memo", "balance", "status", "created_at", "updated_at", "deleted_at", "unconfirmed_balance", "total_balance"}
+
+// accountCsvImportRequiredColumns must be present in the header of an imported CSV file. The memo column is optional
+var accountCsvImportRequiredColumns = []string{"address", "name", "rank", "status"}
+
+func CreateAccountCsvRow(account *entities.Account) []string {
+	memo := ""
+	if account.Memo != nil {
+		memo = *account.Memo
+	}
+	deletedAt := ""
+	if account.IsDeleted() {
+		deletedAt = strconv.FormatInt(account.DeletedAt, 10)
+	}
+	return []string{
+		strconv.FormatInt(account.Id, 10),
+		account.Address,
+		account.Name,
+		strconv.Itoa(int(account.Rank)),
+		memo,
+		account.Balance.String(),
+		string(account.Status),
+		strconv.FormatInt(account.CreatedAt, 10),
+		strconv.FormatInt(account.UpdatedAt, 10),
+		deletedAt,
+		account.UnconfirmedBalance.String(),
+		account.TotalBalance.String(),
+	}
+}
+
+// GetAccountCsvImportColumnIndexes maps the import columns to their position in the header row
+func GetAccountCsvImportColumnIndexes(header []string) (map[string]int, string) {
+	indexes := make(map[string]int)
+	for index, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		indexes[column] = index
+	}
+	for _, column := range accountCsvImportRequiredColumns {
+		if _, exists := indexes[column]; !exists {
+			return nil, fmt.Sprintf("Column %s is required", column)
+		}
+	}
+	return indexes, ""
+}
+
+// CreatePostCreateAccountRequestDtoFromCsvRow builds the create DTO from a CSV row, the error message is empty for a valid row
+func CreatePostCreateAccountRequestDtoFromCsvRow(indexes map[string]int, record []string) (PostCreateAccountRequestDto, string) {
+	var dto PostCreateAccountRequestDto
+	getValue := func(column string) string {
+		index, exists := indexes[column]
+		if !exists || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+	dto.Address = getValue("address")
+	dto.Name = getValue("name")
+	dto.Status = entities.AccountStatus(getValue("status"))
+	if memo := getValue("memo"); memo != "" {
+		dto.Memo = &memo
+	}
+	if rankValue := getValue("rank"); rankValue != "" {
+		rank, err := strconv.ParseInt(rankValue, 10, 8)
+		if err != nil {
+			return dto, "Rank must be between 0 and 100"
+		}
+		dto.Rank = int8(rank)
+	}
+	return dto, GetPostCreateAccountRequestDtoErrorMessage(&dto)
+}
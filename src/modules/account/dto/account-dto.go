@@ -5,27 +5,62 @@ import (
 )
 
 type AccountDto struct {
-	Id        int64   `json:"id" example:"1"`
-	Address   string  `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
-	Name      string  `json:"name" example:"Main Account"`
-	Rank      int8    `json:"rank" example:"50"`
-	Memo      *string `json:"memo" example:"Important account for transactions"`
-	Balance   string  `json:"balance" example:"12.1234"`
-	Status    string  `json:"status" example:"On"`
-	CreatedAt int64   `json:"created_at" example:"1600000000000"`
-	UpdatedAt int64   `json:"updated_at" example:"1600000000000"`
+	Id                  int64    `json:"id" example:"1"`
+	Address             string   `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Name                string   `json:"name" example:"Main Account"`
+	Rank                int8     `json:"rank" example:"50"`
+	Memo                *string  `json:"memo" example:"Important account for transactions"`
+	Balance             string   `json:"balance" example:"12.1234"`
+	UnconfirmedBalance  string   `json:"unconfirmed_balance" example:"0.5"`
+	TotalBalance        string   `json:"total_balance" example:"12.6234"`
+	Status              string   `json:"status" example:"On"`
+	CreatedAt           int64    `json:"created_at" example:"1600000000000"`
+	UpdatedAt           int64    `json:"updated_at" example:"1600000000000"`
+	DeletedAt           *int64   `json:"deleted_at" example:"1600000000000"`
+	NextRefreshAt       int64    `json:"next_refresh_at" example:"1600000000000"`
+	LastRefreshedAt     *int64   `json:"last_refreshed_at" example:"1600000000000"`
+	RefreshIntervalSec  int      `json:"refresh_interval_sec" example:"0"`
+	ConsecutiveFailures int      `json:"consecutive_failures" example:"0"`
+	LastError           *string  `json:"last_error" example:"address not found"`
+	Tags                []string `json:"tags" example:"cold storage,exchange"`
 }
 
 func CreateAccountDto(account *entities.Account) AccountDto {
+	var deletedAt *int64
+	if account.IsDeleted() {
+		deletedAt = &account.DeletedAt
+	}
+	var lastRefreshedAt *int64
+	if account.LastRefreshedAt != 0 {
+		lastRefreshedAt = &account.LastRefreshedAt
+	}
 	return AccountDto{
-		Id:        account.Id,
-		Address:   account.Address,
-		Name:      account.Name,
-		Rank:      account.Rank,
-		Memo:      account.Memo,
-		Balance:   account.Balance.String(),
-		Status:    string(account.Status),
-		CreatedAt: account.CreatedAt,
-		UpdatedAt: account.UpdatedAt,
+		Id:                  account.Id,
+		Address:             account.Address,
+		Name:                account.Name,
+		Rank:                account.Rank,
+		Memo:                account.Memo,
+		Balance:             account.Balance.String(),
+		UnconfirmedBalance:  account.UnconfirmedBalance.String(),
+		TotalBalance:        account.TotalBalance.String(),
+		Status:              string(account.Status),
+		CreatedAt:           account.CreatedAt,
+		UpdatedAt:           account.UpdatedAt,
+		DeletedAt:           deletedAt,
+		NextRefreshAt:       account.NextRefreshAt,
+		LastRefreshedAt:     lastRefreshedAt,
+		RefreshIntervalSec:  account.RefreshIntervalSec,
+		ConsecutiveFailures: account.ConsecutiveFailures,
+		LastError:           account.LastError,
+		Tags:                CreateAccountTagNameList(account.Tags),
+	}
+}
+
+// CreateAccountTagNameList returns the tag names, an empty list if there are no tags
+func CreateAccountTagNameList(tags []*entities.AccountTag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
 	}
+	return names
 }
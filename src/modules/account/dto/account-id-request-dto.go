@@ -0,0 +1,55 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+)
+
+type AccountIdRequestDto struct {
+	Id int64 `uri:"id" json:"id" validate:"min=1" example:"1"`
+}
+
+var accountIdRequestDtoValidator *validator.Validate
+
+func init() {
+	accountIdRequestDtoValidator = validator.New()
+}
+
+func validateAccountIdRequestDto(dto *AccountIdRequestDto) error {
+	return accountIdRequestDtoValidator.Struct(dto)
+}
+
+// CreateAccountIdRequestDto is the Gin version of handling the request
+func CreateAccountIdRequestDto(c *gin.Context) (AccountIdRequestDto, error) {
+	var dto AccountIdRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		errorMessage := AccountIdRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Validate the DTO
+	if err := validateAccountIdRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := AccountIdRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func AccountIdRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultFieldErrorMessage("Id")
+}
+
+func AccountIdRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,103 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"go-gin-test-job/src/common/validations"
+	"net/url"
+	"strconv"
+)
+
+// AccountRangeFilterDto holds the range filters shared by the account list endpoints
+type AccountRangeFilterDto struct {
+	// Minimum rank
+	RankMin *int8 `form:"rankMin" json:"rankMin" validate:"omitnil,min=0,max=100" minimum:"0" maximum:"100" example:"10"`
+	// Maximum rank
+	RankMax *int8 `form:"rankMax" json:"rankMax" validate:"omitnil,min=0,max=100" minimum:"0" maximum:"100" example:"90"`
+	// Minimum balance, decimal string
+	BalanceMin string `form:"balanceMin" json:"balanceMin" validate:"omitempty,max=64,DecimalValidation" example:"1"`
+	// Maximum balance, decimal string
+	BalanceMax string `form:"balanceMax" json:"balanceMax" validate:"omitempty,max=64,DecimalValidation" example:"100.5"`
+	// Created at or after, unix seconds
+	CreatedFrom *int64 `form:"createdFrom" json:"createdFrom" validate:"omitnil,min=0" minimum:"0" example:"1600000000"`
+	// Created at or before, unix seconds
+	CreatedTo *int64 `form:"createdTo" json:"createdTo" validate:"omitnil,min=0" minimum:"0" example:"1700000000"`
+	// Updated at or after, unix seconds
+	UpdatedFrom *int64 `form:"updatedFrom" json:"updatedFrom" validate:"omitnil,min=0" minimum:"0" example:"1600000000"`
+	// Updated at or before, unix seconds
+	UpdatedTo *int64 `form:"updatedTo" json:"updatedTo" validate:"omitnil,min=0" minimum:"0" example:"1700000000"`
+	// Only accounts with a positive unconfirmed balance. false by default
+	PendingFunds bool `form:"pendingFunds" json:"pendingFunds" default:"false" example:"true"`
+}
+
+func registerAccountRangeFilterDtoValidations(v *validator.Validate) {
+	_ = v.RegisterValidation("DecimalValidation", validations.DecimalValidation)
+}
+
+// GetBalanceMin returns the parsed balanceMin. The value is validated by DecimalValidation
+func (dto *AccountRangeFilterDto) GetBalanceMin() *decimal.Decimal {
+	return parseOptionalDecimal(dto.BalanceMin)
+}
+
+// GetBalanceMax returns the parsed balanceMax. The value is validated by DecimalValidation
+func (dto *AccountRangeFilterDto) GetBalanceMax() *decimal.Decimal {
+	return parseOptionalDecimal(dto.BalanceMax)
+}
+
+// GetRangeErrorMessage checks that every range has its lower bound below its upper bound
+func (dto *AccountRangeFilterDto) GetRangeErrorMessage() string {
+	if dto.RankMin != nil && dto.RankMax != nil && *dto.RankMin > *dto.RankMax {
+		return "RankMin must be less than or equal RankMax"
+	}
+	balanceMin, balanceMax := dto.GetBalanceMin(), dto.GetBalanceMax()
+	if balanceMin != nil && balanceMax != nil && balanceMin.GreaterThan(*balanceMax) {
+		return "BalanceMin must be less than or equal BalanceMax"
+	}
+	if dto.CreatedFrom != nil && dto.CreatedTo != nil && *dto.CreatedFrom > *dto.CreatedTo {
+		return "CreatedFrom must be less than or equal CreatedTo"
+	}
+	if dto.UpdatedFrom != nil && dto.UpdatedTo != nil && *dto.UpdatedFrom > *dto.UpdatedTo {
+		return "UpdatedFrom must be less than or equal UpdatedTo"
+	}
+	return ""
+}
+
+// AccountRangeFilterDtoValidateErrorMessage returns an empty string if the error is not about a range filter field
+func AccountRangeFilterDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	switch err.Field() {
+	case "RankMin", "RankMax":
+		errorMessage = fmt.Sprintf("%s must be between 0 and 100", err.Field())
+	case "BalanceMin", "BalanceMax":
+		errorMessage = fmt.Sprintf("%s must be a decimal number", err.Field())
+	case "CreatedFrom", "CreatedTo", "UpdatedFrom", "UpdatedTo":
+		errorMessage = fmt.Sprintf("%s must be greater than or equal 0", err.Field())
+	}
+	return errorMessage
+}
+
+// getInvalidBoolQueryField returns the first field whose query value is not a bool
+func getInvalidBoolQueryField(query url.Values, fields ...string) string {
+	for _, field := range fields {
+		value := query.Get(field)
+		if value == "" {
+			continue
+		}
+		if _, err := strconv.ParseBool(value); err != nil {
+			return field
+		}
+	}
+	return ""
+}
+
+func parseOptionalDecimal(value string) *decimal.Decimal {
+	if value == "" {
+		return nil
+	}
+	result, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil
+	}
+	return &result
+}
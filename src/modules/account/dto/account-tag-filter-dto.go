@@ -0,0 +1,49 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+	"strings"
+)
+
+// AccountTagFilterDto holds the tags filter shared by the account list endpoints
+type AccountTagFilterDto struct {
+	// Comma-separated list of tags
+	Tags string `form:"tags" json:"tags" validate:"omitempty,max=2048,AccountTagListValidation" example:"cold storage,exchange"`
+	// Tags mode: any matches accounts with at least one of the tags, all matches accounts with every tag
+	TagsMode string `form:"tagsMode" json:"tagsMode" validate:"omitempty,oneof=any all" enums:"any,all" default:"any" example:"all"`
+}
+
+func registerAccountTagFilterDtoValidations(v *validator.Validate) {
+	_ = v.RegisterValidation("AccountTagListValidation", validations.AccountTagListValidation)
+}
+
+// GetTagList returns the normalized tags, empty if the filter is not set
+func (dto *AccountTagFilterDto) GetTagList() []string {
+	if dto.Tags == "" {
+		return make([]string, 0)
+	}
+	return entities.NormalizeAccountTagNames(strings.Split(dto.Tags, ","))
+}
+
+// GetTagsMode returns the tags mode, any by default
+func (dto *AccountTagFilterDto) GetTagsMode() entities.AccountTagsMode {
+	if dto.TagsMode == "" {
+		return entities.AccountTagsModeAny
+	}
+	return entities.AccountTagsMode(dto.TagsMode)
+}
+
+// AccountTagFilterDtoValidateErrorMessage returns an empty string if the error is not about a tags filter field
+func AccountTagFilterDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	switch err.Field() {
+	case "Tags":
+		errorMessage = fmt.Sprintf("%s must be a comma-separated list of at most %d tags, each shorter than or equal to %d characters", err.Field(), entities.AccountTagsMaxCount, entities.AccountTagNameMaxLength)
+	case "TagsMode":
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountTagsModeList, ","))
+	}
+	return errorMessage
+}
@@ -0,0 +1,17 @@
+package accountModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+type AccountTagsResponseDto struct {
+	AccountId int64    `json:"accountId" example:"1"`
+	Tags      []string `json:"tags" example:"cold storage,exchange"`
+}
+
+func CreateAccountTagsResponseDto(accountId int64, tags []*entities.AccountTag) AccountTagsResponseDto {
+	return AccountTagsResponseDto{
+		AccountId: accountId,
+		Tags:      CreateAccountTagNameList(tags),
+	}
+}
@@ -0,0 +1,61 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+)
+
+type DeleteAccountTagRequestDto struct {
+	Id  int64  `uri:"id" json:"id" validate:"min=1" example:"1"`
+	Tag string `uri:"tag" json:"tag" validate:"AccountTagValidation" example:"exchange"`
+}
+
+var deleteAccountTagRequestDtoValidator *validator.Validate
+
+func init() {
+	deleteAccountTagRequestDtoValidator = validator.New()
+	_ = deleteAccountTagRequestDtoValidator.RegisterValidation("AccountTagValidation", validations.AccountTagValidation)
+}
+
+func validateDeleteAccountTagRequestDto(dto *DeleteAccountTagRequestDto) error {
+	return deleteAccountTagRequestDtoValidator.Struct(dto)
+}
+
+// GetTagName returns the normalized tag
+func (dto *DeleteAccountTagRequestDto) GetTagName() string {
+	return entities.NormalizeAccountTagName(dto.Tag)
+}
+
+// CreateDeleteAccountTagRequestDto is the Gin version of handling the request
+func CreateDeleteAccountTagRequestDto(c *gin.Context) (DeleteAccountTagRequestDto, error) {
+	var dto DeleteAccountTagRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessages.DefaultFieldErrorMessage("Id"))
+	}
+	// Validate the DTO
+	if err := validateDeleteAccountTagRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := DeleteAccountTagRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func DeleteAccountTagRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Tag" && err.Tag() == "AccountTagValidation" {
+		errorMessage = accountTagValidateErrorMessage()
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
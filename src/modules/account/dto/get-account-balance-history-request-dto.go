@@ -0,0 +1,97 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	stringUtil "go-gin-test-job/src/utils/string"
+)
+
+const DEFAULT_ACCOUNT_BALANCE_HISTORY_COUNT = 100
+const DEFAULT_ACCOUNT_BALANCE_HISTORY_OFFSET = 0
+
+// GetAccountBalanceHistoryRequestDto from and to are unix seconds and are both inclusive
+type GetAccountBalanceHistoryRequestDto struct {
+	Id     int64  `uri:"id" form:"-" json:"-" validate:"min=1" swaggerignore:"true"`
+	Offset int    `form:"offset" json:"offset" validate:"min=0" default:"0" example:"5"`
+	Count  int    `form:"count" json:"count" validate:"min=1,max=100" default:"100" example:"20"`
+	From   *int64 `form:"from" json:"from" validate:"omitnil,min=0" example:"1600000000"`
+	To     *int64 `form:"to" json:"to" validate:"omitnil,min=0" example:"1700000000"`
+}
+
+var getAccountBalanceHistoryRequestDtoValidator *validator.Validate
+
+func init() {
+	getAccountBalanceHistoryRequestDtoValidator = validator.New()
+}
+
+func getAccountBalanceHistoryRequestDtoDefaultValues(dto *GetAccountBalanceHistoryRequestDto) {
+	if dto.Count == 0 {
+		dto.Count = DEFAULT_ACCOUNT_BALANCE_HISTORY_COUNT
+	}
+}
+
+func validateGetAccountBalanceHistoryRequestDto(dto *GetAccountBalanceHistoryRequestDto) error {
+	return getAccountBalanceHistoryRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetAccountBalanceHistoryRequestDto is the Gin version of handling the request
+func CreateGetAccountBalanceHistoryRequestDto(c *gin.Context) (GetAccountBalanceHistoryRequestDto, error) {
+	var dto GetAccountBalanceHistoryRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessages.DefaultFieldErrorMessage("Id"))
+	}
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := GetAccountBalanceHistoryRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	getAccountBalanceHistoryRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validateGetAccountBalanceHistoryRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetAccountBalanceHistoryRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	if dto.From != nil && dto.To != nil && *dto.From > *dto.To {
+		return dto, errorHelpers.RespondBadRequestError(c, "From must be less than or equal To")
+	}
+	return dto, nil
+}
+
+func GetAccountBalanceHistoryRequestDtoQueryParseErrorMessage(err error) string {
+	var errorMessage string
+	if stringUtil.CaseInsensitiveContains(err.Error(), "\"offset\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".offset") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("offset")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"count\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".count") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("count")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"from\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".from") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("from")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"to\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".to") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("to")
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
+
+func GetAccountBalanceHistoryRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Count" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Count" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be less than or equal %s", err.Field(), err.Param())
+	} else if (err.Field() == "Offset" || err.Field() == "From" || err.Field() == "To") && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,46 @@
+package accountModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+type AccountBalanceHistoryDto struct {
+	Id         int64  `json:"id" example:"1"`
+	AccountId  int64  `json:"accountId" example:"1"`
+	OldBalance string `json:"oldBalance" example:"12.1234"`
+	NewBalance string `json:"newBalance" example:"10.1234"`
+	Delta      string `json:"delta" example:"-2"`
+	Source     string `json:"source" example:"bitcore"`
+	CreatedAt  int64  `json:"created_at" example:"1600000000"`
+}
+
+type GetAccountBalanceHistoryResponseDto struct {
+	Offset int                        `json:"offset"`
+	Count  int                        `json:"count"`
+	Total  int64                      `json:"total"`
+	List   []AccountBalanceHistoryDto `json:"list"`
+}
+
+func CreateAccountBalanceHistoryDto(history *entities.AccountBalanceHistory) AccountBalanceHistoryDto {
+	return AccountBalanceHistoryDto{
+		Id:         history.Id,
+		AccountId:  history.AccountId,
+		OldBalance: history.OldBalance.String(),
+		NewBalance: history.NewBalance.String(),
+		Delta:      history.Delta.String(),
+		Source:     history.Source,
+		CreatedAt:  history.CreatedAt,
+	}
+}
+
+func CreateGetAccountBalanceHistoryResponseDto(offset int, count int, total int64, histories []*entities.AccountBalanceHistory) GetAccountBalanceHistoryResponseDto {
+	var dto GetAccountBalanceHistoryResponseDto
+	dto.Offset = offset
+	dto.Count = count
+	dto.Total = total
+	dto.List = make([]AccountBalanceHistoryDto, 0)
+	for _, history := range histories {
+		dto.List = append(dto.List, CreateAccountBalanceHistoryDto(history))
+	}
+	return dto
+}
@@ -0,0 +1,57 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+)
+
+type GetAccountByAddressRequestDto struct {
+	Address string `uri:"address" json:"address" validate:"AccountAddressValidation" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+}
+
+var getAccountByAddressRequestDtoValidator *validator.Validate
+
+func init() {
+	getAccountByAddressRequestDtoValidator = validator.New()
+	_ = getAccountByAddressRequestDtoValidator.RegisterValidation("AccountAddressValidation", validations.AccountAddressValidation)
+}
+
+func validateGetAccountByAddressRequestDto(dto *GetAccountByAddressRequestDto) error {
+	return getAccountByAddressRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetAccountByAddressRequestDto is the Gin version of handling the request
+func CreateGetAccountByAddressRequestDto(c *gin.Context) (GetAccountByAddressRequestDto, error) {
+	var dto GetAccountByAddressRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		errorMessage := GetAccountByAddressRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Validate the DTO
+	if err := validateGetAccountByAddressRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetAccountByAddressRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func GetAccountByAddressRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultQueryParseErrorMessage()
+}
+
+func GetAccountByAddressRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Address" && err.Tag() == "AccountAddressValidation" {
+		errorMessage = fmt.Sprintf("%s format is wrong", err.Field())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
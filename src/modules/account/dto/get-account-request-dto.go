@@ -9,18 +9,22 @@ import (
 	"go-gin-test-job/src/common/validations"
 	"go-gin-test-job/src/database/entities"
 	stringUtil "go-gin-test-job/src/utils/string"
+	"net/url"
 	"strings"
 )
 
 const DEFAULT_ACCOUNT_COUNT = 100
 const DEFAULT_ACCOUNT_OFFSET = 0
+const DEFAULT_ACCOUNT_SEARCH_MODE = string(entities.AccountSearchModeContains)
 
 var GetAvailableAccountSortField = map[string]string{
 	"id":         "account.id",
 	"updated_at": "account.updated_at",
 	"address":    "account.address",
 	"name":       "account.name",
-	"rank":       "account.rank",
+	"rank":       "account.account_rank",
+	"balance":    "account.balance",
+	"created_at": "account.created_at",
 }
 
 var GetAvailableAccountSortFieldList = func() []string {
@@ -32,11 +36,16 @@ var GetAvailableAccountSortFieldList = func() []string {
 }()
 
 type GetAccountRequestDto struct {
-	Offset  int                    `form:"offset" json:"offset" validate:"min=0" default:"0" example:"5"`
-	Count   int                    `form:"count" json:"count" validate:"min=1,max=100" default:"100" example:"20"`
-	Status  entities.AccountStatus `form:"status" json:"status" validate:"omitempty,AccountStatusValidation" example:"On"`
-	OrderBy string                 `form:"orderBy" json:"orderBy" validate:"omitempty,max=255" example:"id ASC"`
-	Search  string                 `form:"search" json:"search" validate:"omitempty,max=255" example:"bitcoin"`
+	Offset         int                    `form:"offset" json:"offset" validate:"min=0" default:"0" example:"5"`
+	Count          int                    `form:"count" json:"count" validate:"min=1,max=100" default:"100" example:"20"`
+	Status         entities.AccountStatus `form:"status" json:"status" validate:"omitempty,AccountStatusValidation" example:"On"`
+	OrderBy        string                 `form:"orderBy" json:"orderBy" validate:"omitempty,max=255" example:"id ASC"`
+	Search         string                 `form:"search" json:"search" validate:"omitempty,max=255" example:"bitcoin"`
+	SearchMode     string                 `form:"searchMode" json:"searchMode" validate:"oneof=contains prefix fulltext" default:"contains" example:"fulltext"`
+	IncludeDeleted bool                   `form:"includeDeleted" json:"includeDeleted" default:"false" example:"false"`
+	Cursor         string                 `form:"cursor" json:"cursor" validate:"omitempty,max=2048" example:"eyJzIjoiaWQgQVNDIiwidiI6WyI1Il19"`
+	AccountRangeFilterDto
+	AccountTagFilterDto
 }
 
 var getAccountRequestDtoValidator *validator.Validate
@@ -44,12 +53,17 @@ var getAccountRequestDtoValidator *validator.Validate
 func init() {
 	getAccountRequestDtoValidator = validator.New()
 	_ = getAccountRequestDtoValidator.RegisterValidation("AccountStatusValidation", validations.AccountStatusValidation)
+	registerAccountRangeFilterDtoValidations(getAccountRequestDtoValidator)
+	registerAccountTagFilterDtoValidations(getAccountRequestDtoValidator)
 }
 
 func getAccountRequestDtoDefaultValues(dto *GetAccountRequestDto) {
 	if dto.Count == 0 {
 		dto.Count = DEFAULT_ACCOUNT_COUNT
 	}
+	if dto.SearchMode == "" {
+		dto.SearchMode = DEFAULT_ACCOUNT_SEARCH_MODE
+	}
 }
 
 func validateGetAccountRequestDto(dto *GetAccountRequestDto) error {
@@ -61,7 +75,7 @@ func CreateGetAccountRequestDto(c *gin.Context) (GetAccountRequestDto, error) {
 	var dto GetAccountRequestDto
 	// Parse query params into DTO
 	if err := c.ShouldBindQuery(&dto); err != nil {
-		errorMessage := GetAccountRequestDtoQueryParseErrorMessage(err)
+		errorMessage := GetAccountRequestDtoQueryParseErrorMessage(err, c.Request.URL.Query())
 		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
 	}
 	// Set default values
@@ -73,16 +87,25 @@ func CreateGetAccountRequestDto(c *gin.Context) (GetAccountRequestDto, error) {
 			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
 		}
 	}
+	if errorMessage := dto.GetRangeErrorMessage(); errorMessage != "" {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	if dto.Cursor != "" && dto.Offset != 0 {
+		return dto, errorHelpers.RespondBadRequestError(c, "Offset cannot be used with cursor")
+	}
 	dto.Status = entities.AccountStatus(strings.Trim(string(dto.Status), "\""))
 	return dto, nil
 }
 
-func GetAccountRequestDtoQueryParseErrorMessage(err error) string {
+func GetAccountRequestDtoQueryParseErrorMessage(err error, query url.Values) string {
 	var errorMessage string
+	boolField := getInvalidBoolQueryField(query, "includeDeleted", "pendingFunds")
 	if stringUtil.CaseInsensitiveContains(err.Error(), "\"offset\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".offset") {
 		errorMessage = errorMessages.DefaultFieldErrorMessage("offset")
 	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"count\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".count") {
 		errorMessage = errorMessages.DefaultFieldErrorMessage("count")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "ParseBool") && boolField != "" {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(boolField)
 	} else {
 		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
 	}
@@ -103,6 +126,14 @@ func GetAccountRequestDtoValidateErrorMessage(err validator.FieldError) string {
 		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
 	} else if err.Field() == "Search" && err.Tag() == "max" {
 		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if err.Field() == "SearchMode" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountSearchModeList, ","))
+	} else if err.Field() == "Cursor" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if tagErrorMessage := AccountTagFilterDtoValidateErrorMessage(err); tagErrorMessage != "" {
+		errorMessage = tagErrorMessage
+	} else if rangeErrorMessage := AccountRangeFilterDtoValidateErrorMessage(err); rangeErrorMessage != "" {
+		errorMessage = rangeErrorMessage
 	} else {
 		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
 	}
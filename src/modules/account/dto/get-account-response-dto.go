@@ -4,21 +4,44 @@ import (
 	"go-gin-test-job/src/database/entities"
 )
 
+// GetAccountResponseDto nextCursor is null on the last page
 type GetAccountResponseDto struct {
-	Offset int          `json:"offset"`
-	Count  int          `json:"count"`
-	Total  int64        `json:"total"`
-	List   []AccountDto `json:"list"`
+	Offset     int          `json:"offset"`
+	Count      int          `json:"count"`
+	Total      int64        `json:"total"`
+	List       []AccountDto `json:"list"`
+	NextCursor *string      `json:"nextCursor"`
 }
 
-func CreateGetAccountResponseDto(offset int, count int, total int64, accounts []*entities.Account) GetAccountResponseDto {
+// GetAccountCursorResponseDto is the page of the cursor mode, it has no offset and the total is not counted
+type GetAccountCursorResponseDto struct {
+	Count      int          `json:"count"`
+	List       []AccountDto `json:"list"`
+	NextCursor *string      `json:"nextCursor"`
+}
+
+func CreateGetAccountResponseDto(offset int, count int, total int64, accounts []*entities.Account, nextCursor *string) GetAccountResponseDto {
 	var dto GetAccountResponseDto
 	dto.Offset = offset
 	dto.Count = count
 	dto.Total = total
-	dto.List = make([]AccountDto, 0)
+	dto.NextCursor = nextCursor
+	dto.List = createAccountDtoList(accounts)
+	return dto
+}
+
+func CreateGetAccountCursorResponseDto(count int, accounts []*entities.Account, nextCursor *string) GetAccountCursorResponseDto {
+	var dto GetAccountCursorResponseDto
+	dto.Count = count
+	dto.NextCursor = nextCursor
+	dto.List = createAccountDtoList(accounts)
+	return dto
+}
+
+func createAccountDtoList(accounts []*entities.Account) []AccountDto {
+	list := make([]AccountDto, 0)
 	for _, account := range accounts {
-		dto.List = append(dto.List, CreateAccountDto(account))
+		list = append(list, CreateAccountDto(account))
 	}
-	return dto
+	return list
 }
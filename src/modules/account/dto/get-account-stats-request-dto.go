@@ -0,0 +1,114 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+	stringUtil "go-gin-test-job/src/utils/string"
+	"net/url"
+	"strings"
+)
+
+const DEFAULT_ACCOUNT_STATS_TOP = 10
+const DEFAULT_ACCOUNT_STATS_RANK_BUCKET_SIZE = 10
+
+type GetAccountStatsRequestDto struct {
+	Top            int                    `form:"top" json:"top" validate:"min=1,max=100" default:"10" example:"5"`
+	RankBucketSize int                    `form:"rankBucketSize" json:"rankBucketSize" validate:"min=1,max=100" default:"10" example:"25"`
+	Status         entities.AccountStatus `form:"status" json:"status" validate:"omitempty,AccountStatusValidation" example:"On"`
+	Search         string                 `form:"search" json:"search" validate:"omitempty,max=255" example:"bitcoin"`
+	SearchMode     string                 `form:"searchMode" json:"searchMode" validate:"oneof=contains prefix fulltext" default:"contains" example:"fulltext"`
+	IncludeDeleted bool                   `form:"includeDeleted" json:"includeDeleted" default:"false" example:"false"`
+	AccountRangeFilterDto
+	AccountTagFilterDto
+}
+
+var getAccountStatsRequestDtoValidator *validator.Validate
+
+func init() {
+	getAccountStatsRequestDtoValidator = validator.New()
+	_ = getAccountStatsRequestDtoValidator.RegisterValidation("AccountStatusValidation", validations.AccountStatusValidation)
+	registerAccountRangeFilterDtoValidations(getAccountStatsRequestDtoValidator)
+	registerAccountTagFilterDtoValidations(getAccountStatsRequestDtoValidator)
+}
+
+func getAccountStatsRequestDtoDefaultValues(dto *GetAccountStatsRequestDto) {
+	if dto.Top == 0 {
+		dto.Top = DEFAULT_ACCOUNT_STATS_TOP
+	}
+	if dto.RankBucketSize == 0 {
+		dto.RankBucketSize = DEFAULT_ACCOUNT_STATS_RANK_BUCKET_SIZE
+	}
+	if dto.SearchMode == "" {
+		dto.SearchMode = DEFAULT_ACCOUNT_SEARCH_MODE
+	}
+}
+
+func validateGetAccountStatsRequestDto(dto *GetAccountStatsRequestDto) error {
+	return getAccountStatsRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetAccountStatsRequestDto is the Gin version of handling the request
+func CreateGetAccountStatsRequestDto(c *gin.Context) (GetAccountStatsRequestDto, error) {
+	var dto GetAccountStatsRequestDto
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := GetAccountStatsRequestDtoQueryParseErrorMessage(err, c.Request.URL.Query())
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	getAccountStatsRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validateGetAccountStatsRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetAccountStatsRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	if errorMessage := dto.GetRangeErrorMessage(); errorMessage != "" {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	dto.Status = entities.AccountStatus(strings.Trim(string(dto.Status), "\""))
+	return dto, nil
+}
+
+func GetAccountStatsRequestDtoQueryParseErrorMessage(err error, query url.Values) string {
+	var errorMessage string
+	boolField := getInvalidBoolQueryField(query, "includeDeleted", "pendingFunds")
+	if stringUtil.CaseInsensitiveContains(err.Error(), "\"top\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".top") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("top")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"rankBucketSize\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".rankBucketSize") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("rankBucketSize")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "ParseBool") && boolField != "" {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(boolField)
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
+
+func GetAccountStatsRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if (err.Field() == "Top" || err.Field() == "RankBucketSize") && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if (err.Field() == "Top" || err.Field() == "RankBucketSize") && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be less than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Status" && err.Tag() == "AccountStatusValidation" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountStatusList, ","))
+	} else if err.Field() == "Search" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if err.Field() == "SearchMode" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountSearchModeList, ","))
+	} else if tagErrorMessage := AccountTagFilterDtoValidateErrorMessage(err); tagErrorMessage != "" {
+		errorMessage = tagErrorMessage
+	} else if rangeErrorMessage := AccountRangeFilterDtoValidateErrorMessage(err); rangeErrorMessage != "" {
+		errorMessage = rangeErrorMessage
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,67 @@
+package accountModuleDto
+
+import (
+	"github.com/shopspring/decimal"
+	"go-gin-test-job/src/database/entities"
+)
+
+// ACCOUNT_MAX_RANK is the upper bound of the rank histogram, ranks are validated to be in [0, 100]
+const ACCOUNT_MAX_RANK = 100
+
+type AccountStatusStatsDto struct {
+	Status  string `json:"status" example:"On"`
+	Count   int64  `json:"count" example:"10"`
+	Balance string `json:"balance" example:"12.1234"`
+}
+
+type AccountRankBucketDto struct {
+	RankFrom int   `json:"rankFrom" example:"0"`
+	RankTo   int   `json:"rankTo" example:"9"`
+	Count    int64 `json:"count" example:"3"`
+}
+
+// GetAccountStatsResponseDto oldestUpdatedAt is the oldest updated_at among the accounts with the On status, null if there are none
+type GetAccountStatsResponseDto struct {
+	Total           int64                   `json:"total" example:"20"`
+	TotalBalance    string                  `json:"totalBalance" example:"24.2468"`
+	ByStatus        []AccountStatusStatsDto `json:"byStatus"`
+	RankHistogram   []AccountRankBucketDto  `json:"rankHistogram"`
+	TopByBalance    []AccountDto            `json:"topByBalance"`
+	OldestUpdatedAt *int64                  `json:"oldestUpdatedAt" example:"1600000000"`
+}
+
+// CreateGetAccountStatsResponseDto lists every status and every rank bucket, including the empty ones
+func CreateGetAccountStatsResponseDto(statusStats []*entities.AccountStatusStats, rankBuckets []*entities.AccountRankBucket, rankBucketSize int, topAccounts []*entities.Account, oldestUpdatedAt *int64) GetAccountStatsResponseDto {
+	var dto GetAccountStatsResponseDto
+	totalBalance := decimal.Zero
+	dto.ByStatus = make([]AccountStatusStatsDto, 0, len(entities.AccountStatusList))
+	for _, status := range entities.AccountStatusList {
+		statusStatsDto := AccountStatusStatsDto{Status: status, Balance: decimal.Zero.String()}
+		for _, stats := range statusStats {
+			if string(stats.Status) == status {
+				statusStatsDto.Count = stats.Count
+				statusStatsDto.Balance = stats.Balance.String()
+				dto.Total += stats.Count
+				totalBalance = totalBalance.Add(stats.Balance)
+			}
+		}
+		dto.ByStatus = append(dto.ByStatus, statusStatsDto)
+	}
+	dto.TotalBalance = totalBalance.String()
+	dto.RankHistogram = make([]AccountRankBucketDto, 0)
+	for rankFrom := 0; rankFrom <= ACCOUNT_MAX_RANK; rankFrom += rankBucketSize {
+		bucketDto := AccountRankBucketDto{RankFrom: rankFrom, RankTo: min(rankFrom+rankBucketSize-1, ACCOUNT_MAX_RANK)}
+		for _, bucket := range rankBuckets {
+			if bucket.RankFrom == rankFrom {
+				bucketDto.Count = bucket.Count
+			}
+		}
+		dto.RankHistogram = append(dto.RankHistogram, bucketDto)
+	}
+	dto.TopByBalance = make([]AccountDto, 0)
+	for _, account := range topAccounts {
+		dto.TopByBalance = append(dto.TopByBalance, CreateAccountDto(account))
+	}
+	dto.OldestUpdatedAt = oldestUpdatedAt
+	return dto
+}
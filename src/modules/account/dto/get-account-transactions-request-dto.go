@@ -0,0 +1,87 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	stringUtil "go-gin-test-job/src/utils/string"
+)
+
+const DEFAULT_ACCOUNT_TRANSACTIONS_COUNT = 100
+const DEFAULT_ACCOUNT_TRANSACTIONS_OFFSET = 0
+
+type GetAccountTransactionsRequestDto struct {
+	Id     int64 `uri:"id" form:"-" json:"-" validate:"min=1" swaggerignore:"true"`
+	Offset int   `form:"offset" json:"offset" validate:"min=0" default:"0" example:"5"`
+	Count  int   `form:"count" json:"count" validate:"min=1,max=100" default:"100" example:"20"`
+}
+
+var getAccountTransactionsRequestDtoValidator *validator.Validate
+
+func init() {
+	getAccountTransactionsRequestDtoValidator = validator.New()
+}
+
+func getAccountTransactionsRequestDtoDefaultValues(dto *GetAccountTransactionsRequestDto) {
+	if dto.Count == 0 {
+		dto.Count = DEFAULT_ACCOUNT_TRANSACTIONS_COUNT
+	}
+}
+
+func validateGetAccountTransactionsRequestDto(dto *GetAccountTransactionsRequestDto) error {
+	return getAccountTransactionsRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetAccountTransactionsRequestDto is the Gin version of handling the request
+func CreateGetAccountTransactionsRequestDto(c *gin.Context) (GetAccountTransactionsRequestDto, error) {
+	var dto GetAccountTransactionsRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessages.DefaultFieldErrorMessage("Id"))
+	}
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := GetAccountTransactionsRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	getAccountTransactionsRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validateGetAccountTransactionsRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetAccountTransactionsRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func GetAccountTransactionsRequestDtoQueryParseErrorMessage(err error) string {
+	var errorMessage string
+	if stringUtil.CaseInsensitiveContains(err.Error(), "\"offset\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".offset") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("offset")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"count\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".count") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("count")
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
+
+func GetAccountTransactionsRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Count" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Count" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be less than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Offset" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,55 @@
+package accountModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+// AccountTransactionDto blockHeight and blockTime are null while the transaction is unconfirmed
+type AccountTransactionDto struct {
+	Id            int64  `json:"id" example:"1"`
+	AccountId     int64  `json:"accountId" example:"1"`
+	Txid          string `json:"txid" example:"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"`
+	BlockHeight   *int64 `json:"blockHeight" example:"170"`
+	BlockTime     *int64 `json:"blockTime" example:"1231731025"`
+	AmountIn      string `json:"amountIn" example:"0"`
+	AmountOut     string `json:"amountOut" example:"50"`
+	Fee           string `json:"fee" example:"0.0001"`
+	Confirmations int64  `json:"confirmations" example:"6"`
+	CreatedAt     int64  `json:"created_at" example:"1600000000"`
+	UpdatedAt     int64  `json:"updated_at" example:"1600000000"`
+}
+
+type GetAccountTransactionsResponseDto struct {
+	Offset int                     `json:"offset"`
+	Count  int                     `json:"count"`
+	Total  int64                   `json:"total"`
+	List   []AccountTransactionDto `json:"list"`
+}
+
+func CreateAccountTransactionDto(transaction *entities.AccountTransaction) AccountTransactionDto {
+	return AccountTransactionDto{
+		Id:            transaction.Id,
+		AccountId:     transaction.AccountId,
+		Txid:          transaction.Txid,
+		BlockHeight:   transaction.BlockHeight,
+		BlockTime:     transaction.BlockTime,
+		AmountIn:      transaction.AmountIn.String(),
+		AmountOut:     transaction.AmountOut.String(),
+		Fee:           transaction.Fee.String(),
+		Confirmations: transaction.Confirmations,
+		CreatedAt:     transaction.CreatedAt,
+		UpdatedAt:     transaction.UpdatedAt,
+	}
+}
+
+func CreateGetAccountTransactionsResponseDto(offset int, count int, total int64, transactions []*entities.AccountTransaction) GetAccountTransactionsResponseDto {
+	var dto GetAccountTransactionsResponseDto
+	dto.Offset = offset
+	dto.Count = count
+	dto.Total = total
+	dto.List = make([]AccountTransactionDto, 0)
+	for _, transaction := range transactions {
+		dto.List = append(dto.List, CreateAccountTransactionDto(transaction))
+	}
+	return dto
+}
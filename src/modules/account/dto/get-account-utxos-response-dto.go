@@ -0,0 +1,55 @@
+package accountModuleDto
+
+import (
+	"github.com/shopspring/decimal"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+)
+
+// AccountUtxoDto confirmations is 0 while the output is in the mempool
+type AccountUtxoDto struct {
+	AccountId     int64  `json:"accountId" example:"1"`
+	Address       string `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Txid          string `json:"txid" example:"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"`
+	Vout          int64  `json:"vout" example:"0"`
+	Value         string `json:"value" example:"0.5"`
+	Confirmations int64  `json:"confirmations" example:"6"`
+	ScriptType    string `json:"scriptType" example:"p2pkh"`
+}
+
+// GetAccountUtxosResponseDto value is the sum of the listed outputs
+type GetAccountUtxosResponseDto struct {
+	AccountId int64            `json:"accountId" example:"1"`
+	Address   string           `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Count     int              `json:"count" example:"3"`
+	Value     string           `json:"value" example:"1.25"`
+	List      []AccountUtxoDto `json:"list"`
+}
+
+func CreateAccountUtxoDto(account *entities.Account, utxo blockchain.AddressUtxo) AccountUtxoDto {
+	return AccountUtxoDto{
+		AccountId:     account.Id,
+		Address:       account.Address,
+		Txid:          utxo.Txid,
+		Vout:          utxo.Vout,
+		Value:         utxo.Value.String(),
+		Confirmations: utxo.Confirmations,
+		ScriptType:    utxo.ScriptType,
+	}
+}
+
+func CreateGetAccountUtxosResponseDto(account *entities.Account, utxos []blockchain.AddressUtxo) GetAccountUtxosResponseDto {
+	value := decimal.Zero
+	list := make([]AccountUtxoDto, 0, len(utxos))
+	for _, utxo := range utxos {
+		value = value.Add(utxo.Value)
+		list = append(list, CreateAccountUtxoDto(account, utxo))
+	}
+	return GetAccountUtxosResponseDto{
+		AccountId: account.Id,
+		Address:   account.Address,
+		Count:     len(list),
+		Value:     value.String(),
+		List:      list,
+	}
+}
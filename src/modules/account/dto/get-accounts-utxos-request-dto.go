@@ -0,0 +1,122 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+	stringUtil "go-gin-test-job/src/utils/string"
+	"strings"
+)
+
+// DEFAULT_ACCOUNTS_UTXOS_COUNT is lower than the list count, the outputs of every account are read from the blockchain api
+const DEFAULT_ACCOUNTS_UTXOS_COUNT = 20
+const DEFAULT_ACCOUNTS_UTXOS_OFFSET = 0
+
+// GetAccountsUtxosRequestDto offset and count page the accounts, dustThreshold leaves out the dust outputs
+type GetAccountsUtxosRequestDto struct {
+	Offset         int                    `form:"offset" json:"offset" validate:"min=0" default:"0" example:"5"`
+	Count          int                    `form:"count" json:"count" validate:"min=1,max=100" default:"20" example:"20"`
+	Status         entities.AccountStatus `form:"status" json:"status" validate:"omitempty,AccountStatusValidation" example:"On"`
+	Search         string                 `form:"search" json:"search" validate:"omitempty,max=255" example:"bitcoin"`
+	SearchMode     string                 `form:"searchMode" json:"searchMode" validate:"oneof=contains prefix fulltext" default:"contains" example:"fulltext"`
+	IncludeDeleted bool                   `form:"includeDeleted" json:"includeDeleted" default:"false" example:"false"`
+	DustThreshold  string                 `form:"dustThreshold" json:"dustThreshold" validate:"omitempty,max=64,DecimalValidation" example:"0.00001"`
+	DustOnly       bool                   `form:"dustOnly" json:"dustOnly" default:"false" example:"false"`
+	AccountRangeFilterDto
+	AccountTagFilterDto
+}
+
+var getAccountsUtxosRequestDtoValidator *validator.Validate
+
+func init() {
+	getAccountsUtxosRequestDtoValidator = validator.New()
+	_ = getAccountsUtxosRequestDtoValidator.RegisterValidation("AccountStatusValidation", validations.AccountStatusValidation)
+	registerAccountRangeFilterDtoValidations(getAccountsUtxosRequestDtoValidator)
+	registerAccountTagFilterDtoValidations(getAccountsUtxosRequestDtoValidator)
+}
+
+func getAccountsUtxosRequestDtoDefaultValues(dto *GetAccountsUtxosRequestDto) {
+	if dto.Count == 0 {
+		dto.Count = DEFAULT_ACCOUNTS_UTXOS_COUNT
+	}
+	if dto.SearchMode == "" {
+		dto.SearchMode = DEFAULT_ACCOUNT_SEARCH_MODE
+	}
+}
+
+func validateGetAccountsUtxosRequestDto(dto *GetAccountsUtxosRequestDto) error {
+	return getAccountsUtxosRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetAccountsUtxosRequestDto is the Gin version of handling the request
+func CreateGetAccountsUtxosRequestDto(c *gin.Context) (GetAccountsUtxosRequestDto, error) {
+	var dto GetAccountsUtxosRequestDto
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := GetAccountsUtxosRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	getAccountsUtxosRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validateGetAccountsUtxosRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetAccountsUtxosRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	if errorMessage := dto.GetRangeErrorMessage(); errorMessage != "" {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	if dto.DustOnly && dto.DustThreshold == "" {
+		return dto, errorHelpers.RespondBadRequestError(c, "DustOnly requires DustThreshold")
+	}
+	dto.Status = entities.AccountStatus(strings.Trim(string(dto.Status), "\""))
+	return dto, nil
+}
+
+// GetDustThreshold returns the parsed dustThreshold. The value is validated by DecimalValidation
+func (dto *GetAccountsUtxosRequestDto) GetDustThreshold() *decimal.Decimal {
+	return parseOptionalDecimal(dto.DustThreshold)
+}
+
+func GetAccountsUtxosRequestDtoQueryParseErrorMessage(err error) string {
+	var errorMessage string
+	if stringUtil.CaseInsensitiveContains(err.Error(), "\"offset\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".offset") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("offset")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"count\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".count") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("count")
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
+
+func GetAccountsUtxosRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if (err.Field() == "Offset" || err.Field() == "Count") && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Count" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be less than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Status" && err.Tag() == "AccountStatusValidation" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountStatusList, ","))
+	} else if err.Field() == "Search" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if err.Field() == "SearchMode" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountSearchModeList, ","))
+	} else if err.Field() == "DustThreshold" {
+		errorMessage = fmt.Sprintf("%s must be a decimal number", err.Field())
+	} else if tagErrorMessage := AccountTagFilterDtoValidateErrorMessage(err); tagErrorMessage != "" {
+		errorMessage = tagErrorMessage
+	} else if rangeErrorMessage := AccountRangeFilterDtoValidateErrorMessage(err); rangeErrorMessage != "" {
+		errorMessage = rangeErrorMessage
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
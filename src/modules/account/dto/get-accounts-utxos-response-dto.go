@@ -0,0 +1,54 @@
+package accountModuleDto
+
+import (
+	"github.com/shopspring/decimal"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+)
+
+// AccountUtxosErrorDto is an account whose outputs could not be read, it is left out of the list
+type AccountUtxosErrorDto struct {
+	AccountId int64  `json:"accountId" example:"1"`
+	Address   string `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Error     string `json:"error" example:"No balance provider answered"`
+}
+
+// GetAccountsUtxosResponseDto offset, count and total are about the accounts, utxoCount and value about the listed outputs
+type GetAccountsUtxosResponseDto struct {
+	Offset    int                    `json:"offset"`
+	Count     int                    `json:"count"`
+	Total     int64                  `json:"total"`
+	UtxoCount int                    `json:"utxoCount" example:"12"`
+	Value     string                 `json:"value" example:"3.75"`
+	Failed    []AccountUtxosErrorDto `json:"failed"`
+	List      []AccountUtxoDto       `json:"list"`
+}
+
+func NewGetAccountsUtxosResponseDto(offset int, count int, total int64) *GetAccountsUtxosResponseDto {
+	return &GetAccountsUtxosResponseDto{
+		Offset: offset,
+		Count:  count,
+		Total:  total,
+		Value:  decimal.Zero.String(),
+		Failed: make([]AccountUtxosErrorDto, 0),
+		List:   make([]AccountUtxoDto, 0),
+	}
+}
+
+func (dto *GetAccountsUtxosResponseDto) AddUtxos(account *entities.Account, utxos []blockchain.AddressUtxo) {
+	value := decimal.RequireFromString(dto.Value)
+	for _, utxo := range utxos {
+		value = value.Add(utxo.Value)
+		dto.List = append(dto.List, CreateAccountUtxoDto(account, utxo))
+	}
+	dto.UtxoCount = len(dto.List)
+	dto.Value = value.String()
+}
+
+func (dto *GetAccountsUtxosResponseDto) AddFailed(account *entities.Account, err error) {
+	dto.Failed = append(dto.Failed, AccountUtxosErrorDto{
+		AccountId: account.Id,
+		Address:   account.Address,
+		Error:     err.Error(),
+	})
+}
@@ -0,0 +1,106 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+	stringUtil "go-gin-test-job/src/utils/string"
+	"net/url"
+	"strings"
+)
+
+const DEFAULT_ACCOUNT_EXPORT_FORMAT = "csv"
+
+type GetExportAccountRequestDto struct {
+	Format         string                 `form:"format" json:"format" validate:"oneof=csv" default:"csv" example:"csv"`
+	Status         entities.AccountStatus `form:"status" json:"status" validate:"omitempty,AccountStatusValidation" example:"On"`
+	OrderBy        string                 `form:"orderBy" json:"orderBy" validate:"omitempty,max=255" example:"id ASC"`
+	Search         string                 `form:"search" json:"search" validate:"omitempty,max=255" example:"bitcoin"`
+	SearchMode     string                 `form:"searchMode" json:"searchMode" validate:"oneof=contains prefix fulltext" default:"contains" example:"fulltext"`
+	IncludeDeleted bool                   `form:"includeDeleted" json:"includeDeleted" default:"false" example:"false"`
+	AccountRangeFilterDto
+	AccountTagFilterDto
+}
+
+var getExportAccountRequestDtoValidator *validator.Validate
+
+func init() {
+	getExportAccountRequestDtoValidator = validator.New()
+	_ = getExportAccountRequestDtoValidator.RegisterValidation("AccountStatusValidation", validations.AccountStatusValidation)
+	registerAccountRangeFilterDtoValidations(getExportAccountRequestDtoValidator)
+	registerAccountTagFilterDtoValidations(getExportAccountRequestDtoValidator)
+}
+
+func getExportAccountRequestDtoDefaultValues(dto *GetExportAccountRequestDto) {
+	if dto.Format == "" {
+		dto.Format = DEFAULT_ACCOUNT_EXPORT_FORMAT
+	}
+	if dto.SearchMode == "" {
+		dto.SearchMode = DEFAULT_ACCOUNT_SEARCH_MODE
+	}
+}
+
+func validateGetExportAccountRequestDto(dto *GetExportAccountRequestDto) error {
+	return getExportAccountRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetExportAccountRequestDto is the Gin version of handling the request
+func CreateGetExportAccountRequestDto(c *gin.Context) (GetExportAccountRequestDto, error) {
+	var dto GetExportAccountRequestDto
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := GetExportAccountRequestDtoQueryParseErrorMessage(err, c.Request.URL.Query())
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	getExportAccountRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validateGetExportAccountRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetExportAccountRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	if errorMessage := dto.GetRangeErrorMessage(); errorMessage != "" {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	dto.Status = entities.AccountStatus(strings.Trim(string(dto.Status), "\""))
+	return dto, nil
+}
+
+func GetExportAccountRequestDtoQueryParseErrorMessage(err error, query url.Values) string {
+	var errorMessage string
+	boolField := getInvalidBoolQueryField(query, "includeDeleted", "pendingFunds")
+	if stringUtil.CaseInsensitiveContains(err.Error(), "ParseBool") && boolField != "" {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(boolField)
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
+
+func GetExportAccountRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Format" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), "csv")
+	} else if err.Field() == "Status" && err.Tag() == "AccountStatusValidation" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountStatusList, ","))
+	} else if err.Field() == "OrderBy" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if err.Field() == "Search" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if err.Field() == "SearchMode" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountSearchModeList, ","))
+	} else if tagErrorMessage := AccountTagFilterDtoValidateErrorMessage(err); tagErrorMessage != "" {
+		errorMessage = tagErrorMessage
+	} else if rangeErrorMessage := AccountRangeFilterDtoValidateErrorMessage(err); rangeErrorMessage != "" {
+		errorMessage = rangeErrorMessage
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
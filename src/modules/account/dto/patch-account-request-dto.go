@@ -0,0 +1,95 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+	"strings"
+)
+
+// PatchAccountRequestDto RefreshIntervalSec 0 refreshes the account by the interval of its rank
+type PatchAccountRequestDto struct {
+	Id                 int64                   `uri:"id" json:"-" validate:"min=1" swaggerignore:"true"`
+	Address            *string                 `json:"address,omitempty" swaggerignore:"true"`
+	Name               *string                 `json:"name" validate:"omitnil,NotEmpty,max=255" example:"Main Account"`
+	Rank               *int8                   `json:"rank" validate:"omitnil,min=0,max=100" example:"50"`
+	Memo               *string                 `json:"memo" validate:"omitnil,max=65535" example:"Important account for transactions"`
+	Status             *entities.AccountStatus `json:"status" validate:"omitnil,AccountStatusValidation" enums:"On,Off" example:"On"`
+	RefreshIntervalSec *int                    `json:"refreshIntervalSec" validate:"omitnil,min=0,max=604800" example:"600"`
+}
+
+var patchAccountRequestDtoValidator *validator.Validate
+
+func init() {
+	patchAccountRequestDtoValidator = validator.New()
+	_ = patchAccountRequestDtoValidator.RegisterValidation("NotEmpty", validations.NotEmpty)
+	_ = patchAccountRequestDtoValidator.RegisterValidation("AccountStatusValidation", validations.AccountStatusValidation)
+}
+
+func validatePatchAccountRequestDto(dto *PatchAccountRequestDto) error {
+	return patchAccountRequestDtoValidator.Struct(dto)
+}
+
+// IsEmpty reports whether the request does not change any field
+func (dto *PatchAccountRequestDto) IsEmpty() bool {
+	return dto.Name == nil && dto.Rank == nil && dto.Memo == nil && dto.Status == nil && dto.RefreshIntervalSec == nil
+}
+
+// CreatePatchAccountRequestDto is the Gin version for handling the request
+func CreatePatchAccountRequestDto(c *gin.Context) (PatchAccountRequestDto, error) {
+	var dto PatchAccountRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessages.DefaultFieldErrorMessage("Id"))
+	}
+	// Parse body params into DTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errorMessage := PatchAccountRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Address is the account identity on the blockchain and is never updated
+	if dto.Address != nil {
+		return dto, errorHelpers.RespondBadRequestError(c, "Address cannot be changed")
+	}
+	// Validate the DTO
+	if err := validatePatchAccountRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := PatchAccountRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	if dto.IsEmpty() {
+		return dto, errorHelpers.RespondBadRequestError(c, "At least one field must be provided")
+	}
+	return dto, nil
+}
+
+func PatchAccountRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultQueryParseErrorMessage()
+}
+
+func PatchAccountRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Status" && err.Tag() == "AccountStatusValidation" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), strings.Join(entities.AccountStatusList, ","))
+	} else if err.Field() == "Name" && err.Tag() == "NotEmpty" {
+		errorMessage = fmt.Sprintf("%s must not be empty", err.Field())
+	} else if err.Field() == "Name" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if err.Field() == "Rank" && (err.Tag() == "min" || err.Tag() == "max") {
+		errorMessage = fmt.Sprintf("%s must be between 0 and 100", err.Field())
+	} else if err.Field() == "RefreshIntervalSec" && (err.Tag() == "min" || err.Tag() == "max") {
+		errorMessage = fmt.Sprintf("%s must be between 0 and 604800", err.Field())
+	} else if err.Field() == "Memo" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,78 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+)
+
+type PostAccountTagsRequestDto struct {
+	Id   int64    `uri:"id" json:"-" validate:"min=1" swaggerignore:"true"`
+	Tags []string `json:"tags" validate:"required,min=1,max=20,dive,AccountTagValidation" example:"cold storage,exchange"`
+}
+
+var postAccountTagsRequestDtoValidator *validator.Validate
+
+func init() {
+	postAccountTagsRequestDtoValidator = validator.New()
+	_ = postAccountTagsRequestDtoValidator.RegisterValidation("AccountTagValidation", validations.AccountTagValidation)
+}
+
+func validatePostAccountTagsRequestDto(dto *PostAccountTagsRequestDto) error {
+	return postAccountTagsRequestDtoValidator.Struct(dto)
+}
+
+// GetTagList returns the normalized tags without duplicates
+func (dto *PostAccountTagsRequestDto) GetTagList() []string {
+	return entities.NormalizeAccountTagNames(dto.Tags)
+}
+
+// CreatePostAccountTagsRequestDto is the Gin version for handling the request
+func CreatePostAccountTagsRequestDto(c *gin.Context) (PostAccountTagsRequestDto, error) {
+	var dto PostAccountTagsRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessages.DefaultFieldErrorMessage("Id"))
+	}
+	// Parse body params into DTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errorMessage := PostAccountTagsRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Validate the DTO
+	if err := validatePostAccountTagsRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := PostAccountTagsRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func PostAccountTagsRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultQueryParseErrorMessage()
+}
+
+func PostAccountTagsRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Tags" && (err.Tag() == "required" || err.Tag() == "min") {
+		errorMessage = fmt.Sprintf("%s must contain at least 1 item", err.Field())
+	} else if err.Field() == "Tags" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must contain at most %s items", err.Field(), err.Param())
+	} else if err.Tag() == "AccountTagValidation" {
+		errorMessage = accountTagValidateErrorMessage()
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
+
+func accountTagValidateErrorMessage() string {
+	return fmt.Sprintf("Tag must not be empty, must be shorter than or equal to %d characters and must not contain commas", entities.AccountTagNameMaxLength)
+}
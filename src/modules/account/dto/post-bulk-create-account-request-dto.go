@@ -0,0 +1,77 @@
+package accountModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+)
+
+type BulkCreateAccountMode string
+
+const (
+	// BulkCreateAccountModeAtomic creates all accounts or none of them
+	BulkCreateAccountModeAtomic BulkCreateAccountMode = "atomic"
+	// BulkCreateAccountModeBestEffort creates every valid account and reports the rest
+	BulkCreateAccountModeBestEffort BulkCreateAccountMode = "bestEffort"
+)
+
+type PostBulkCreateAccountRequestDto struct {
+	Mode  BulkCreateAccountMode         `json:"mode" validate:"omitempty,oneof=atomic bestEffort" enums:"atomic,bestEffort" default:"bestEffort" example:"bestEffort"`
+	Items []PostCreateAccountRequestDto `json:"items" validate:"required,min=1,max=500"`
+}
+
+var postBulkCreateAccountRequestDtoValidator *validator.Validate
+
+func init() {
+	postBulkCreateAccountRequestDtoValidator = validator.New()
+}
+
+func postBulkCreateAccountRequestDtoDefaultValues(dto *PostBulkCreateAccountRequestDto) {
+	if dto.Mode == "" {
+		dto.Mode = BulkCreateAccountModeBestEffort
+	}
+}
+
+func validatePostBulkCreateAccountRequestDto(dto *PostBulkCreateAccountRequestDto) error {
+	return postBulkCreateAccountRequestDtoValidator.Struct(dto)
+}
+
+// CreatePostBulkCreateAccountRequestDto is the Gin version for handling the request
+func CreatePostBulkCreateAccountRequestDto(c *gin.Context) (PostBulkCreateAccountRequestDto, error) {
+	var dto PostBulkCreateAccountRequestDto
+	// Parse body params into DTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errorMessage := PostBulkCreateAccountRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	postBulkCreateAccountRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validatePostBulkCreateAccountRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := PostBulkCreateAccountRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func PostBulkCreateAccountRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultQueryParseErrorMessage()
+}
+
+func PostBulkCreateAccountRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Mode" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), "atomic,bestEffort")
+	} else if err.Field() == "Items" && (err.Tag() == "required" || err.Tag() == "min") {
+		errorMessage = fmt.Sprintf("%s must contain at least 1 item", err.Field())
+	} else if err.Field() == "Items" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must contain at most %s items", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,58 @@
+package accountModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+type BulkCreateAccountItemStatus string
+
+const (
+	BulkCreateAccountItemStatusCreated         BulkCreateAccountItemStatus = "created"
+	BulkCreateAccountItemStatusConflict        BulkCreateAccountItemStatus = "conflict"
+	BulkCreateAccountItemStatusValidationError BulkCreateAccountItemStatus = "validationError"
+	BulkCreateAccountItemStatusError           BulkCreateAccountItemStatus = "error"
+	// BulkCreateAccountItemStatusRolledBack marks valid items of a failed atomic request
+	BulkCreateAccountItemStatusRolledBack BulkCreateAccountItemStatus = "rolledBack"
+)
+
+type BulkCreateAccountItemResultDto struct {
+	Index   int                         `json:"index" example:"0"`
+	Address string                      `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Status  BulkCreateAccountItemStatus `json:"status" enums:"created,conflict,validationError,error,rolledBack" example:"created"`
+	Message string                      `json:"message,omitempty" example:"Address already exists"`
+	Account *AccountDto                 `json:"account,omitempty"`
+}
+
+type PostBulkCreateAccountResponseDto struct {
+	Mode    BulkCreateAccountMode            `json:"mode" example:"bestEffort"`
+	Created int                              `json:"created" example:"1"`
+	Failed  int                              `json:"failed" example:"0"`
+	Results []BulkCreateAccountItemResultDto `json:"results"`
+}
+
+func CreateBulkCreateAccountItemResultDto(index int, address string, status BulkCreateAccountItemStatus, message string, account *entities.Account) BulkCreateAccountItemResultDto {
+	var dto BulkCreateAccountItemResultDto
+	dto.Index = index
+	dto.Address = address
+	dto.Status = status
+	dto.Message = message
+	if account != nil {
+		accountDto := CreateAccountDto(account)
+		dto.Account = &accountDto
+	}
+	return dto
+}
+
+func CreatePostBulkCreateAccountResponseDto(mode BulkCreateAccountMode, results []BulkCreateAccountItemResultDto) PostBulkCreateAccountResponseDto {
+	var dto PostBulkCreateAccountResponseDto
+	dto.Mode = mode
+	dto.Results = results
+	for _, result := range results {
+		if result.Status == BulkCreateAccountItemStatusCreated {
+			dto.Created++
+		} else {
+			dto.Failed++
+		}
+	}
+	return dto
+}
@@ -49,6 +49,16 @@ func CreatePostCreateAccountRequestDto(c *gin.Context) (PostCreateAccountRequest
 	return dto, nil
 }
 
+// GetPostCreateAccountRequestDtoErrorMessage validates the DTO outside of a request. Returns an empty string for a valid DTO
+func GetPostCreateAccountRequestDtoErrorMessage(dto *PostCreateAccountRequestDto) string {
+	if err := validatePostCreateAccountRequestDto(dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			return PostCreateAccountRequestDtoValidateErrorMessage(err)
+		}
+	}
+	return ""
+}
+
 func PostCreateAccountRequestDtoQueryParseErrorMessage(err error) string {
 	return errorMessages.DefaultQueryParseErrorMessage()
 }
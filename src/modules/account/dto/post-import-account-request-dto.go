@@ -0,0 +1,30 @@
+package accountModuleDto
+
+import (
+	"github.com/gin-gonic/gin"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"mime/multipart"
+)
+
+type PostImportAccountRequestDto struct {
+	File *multipart.FileHeader `form:"file" json:"-"`
+}
+
+// CreatePostImportAccountRequestDto is the Gin version for handling the request
+func CreatePostImportAccountRequestDto(c *gin.Context) (PostImportAccountRequestDto, error) {
+	var dto PostImportAccountRequestDto
+	// Parse multipart form into DTO
+	if err := c.ShouldBind(&dto); err != nil {
+		errorMessage := PostImportAccountRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	if dto.File == nil {
+		return dto, errorHelpers.RespondBadRequestError(c, "File is required")
+	}
+	return dto, nil
+}
+
+func PostImportAccountRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultQueryParseErrorMessage()
+}
@@ -0,0 +1,47 @@
+package accountModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+type ImportAccountAcceptedRowDto struct {
+	Line    int        `json:"line" example:"2"`
+	Account AccountDto `json:"account"`
+}
+
+type ImportAccountRejectedRowDto struct {
+	Line    int    `json:"line" example:"3"`
+	Address string `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Message string `json:"message" example:"Address already exists"`
+}
+
+type PostImportAccountResponseDto struct {
+	Accepted     int                           `json:"accepted" example:"1"`
+	Rejected     int                           `json:"rejected" example:"1"`
+	AcceptedRows []ImportAccountAcceptedRowDto `json:"acceptedRows"`
+	RejectedRows []ImportAccountRejectedRowDto `json:"rejectedRows"`
+}
+
+func NewPostImportAccountResponseDto() *PostImportAccountResponseDto {
+	return &PostImportAccountResponseDto{
+		AcceptedRows: make([]ImportAccountAcceptedRowDto, 0),
+		RejectedRows: make([]ImportAccountRejectedRowDto, 0),
+	}
+}
+
+func (dto *PostImportAccountResponseDto) AddAccepted(line int, account *entities.Account) {
+	dto.Accepted++
+	dto.AcceptedRows = append(dto.AcceptedRows, ImportAccountAcceptedRowDto{
+		Line:    line,
+		Account: CreateAccountDto(account),
+	})
+}
+
+func (dto *PostImportAccountResponseDto) AddRejected(line int, address string, message string) {
+	dto.Rejected++
+	dto.RejectedRows = append(dto.RejectedRows, ImportAccountRejectedRowDto{
+		Line:    line,
+		Address: address,
+		Message: message,
+	})
+}
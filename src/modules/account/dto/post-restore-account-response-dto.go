@@ -0,0 +1,9 @@
+package accountModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+func CreatePostRestoreAccountResponseDto(account *entities.Account) AccountDto {
+	return CreateAccountDto(account)
+}
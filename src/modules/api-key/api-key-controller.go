@@ -0,0 +1,83 @@
+package apiKeyModule
+
+import (
+	"github.com/gin-gonic/gin"
+	apiKeyModuleDto "go-gin-test-job/src/modules/api-key/dto"
+)
+
+// GetApiKeys Get api keys
+// @Summary Get api keys
+// @Description Get the api keys, newest first. Only the prefix of a key is returned, the revoked keys are left out unless includeRevoked is set
+// @Tags ApiKey
+// @Accept json
+// @Produce json
+// @Param offset query int false "This is paging offset. 0 by default" minimum(0) default(0)
+// @Param count query int false "Max item count in single response. 20 by default" minimum(1) maximum(100) default(20)
+// @Param includeRevoked query bool false "Include the revoked keys. false by default" default(false)
+// @Param X-API-Key header string true "Api key with the api-key:manage scope"
+// @Success 200 {object} apiKeyModuleDto.GetApiKeysResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Router /api-key [get]
+func GetApiKeys(c *gin.Context) {
+	dto, err := apiKeyModuleDto.CreateGetApiKeysRequestDto(c)
+	if err != nil {
+		return
+	}
+	apiKeys, total := getApiKeys(dto.IncludeRevoked, dto.Offset, dto.Count)
+	c.JSON(200, apiKeyModuleDto.CreateGetApiKeysResponseDto(dto.Offset, dto.Count, total, apiKeys))
+}
+
+// CreateApiKey Create api key
+// @Summary Create api key
+// @Description Create an api key with the given scopes. The key is returned only in this response, only its hash is stored. A key without expiresInSec never expires
+// @Tags ApiKey
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Api key with the api-key:manage scope"
+// @Param request body apiKeyModuleDto.PostCreateApiKeyRequestDto true "Request body"
+// @Success 200 {object} apiKeyModuleDto.PostCreateApiKeyResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 500 {object} errorHelpers.ResponseInternalErrorHTTP{}
+// @Router /api-key [post]
+func CreateApiKey(c *gin.Context) {
+	dto, err := apiKeyModuleDto.CreatePostCreateApiKeyRequestDto(c)
+	if err != nil {
+		return
+	}
+	apiKey, key, err := createApiKey(c, dto.Name, dto.Scopes, dto.ExpiresInSec)
+	if err != nil {
+		return
+	}
+	c.JSON(200, apiKeyModuleDto.CreatePostCreateApiKeyResponseDto(apiKey, key))
+}
+
+// RevokeApiKey Revoke api key
+// @Summary Revoke api key
+// @Description Revoke an api key, it stops working right away. A key is rotated by creating a new one and revoking the old one
+// @Tags ApiKey
+// @Accept json
+// @Produce json
+// @Param id path int true "Api key id" minimum(1)
+// @Param X-API-Key header string true "Api key with the api-key:manage scope"
+// @Success 200 {object} apiKeyModuleDto.ApiKeyDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Failure 409 {object} errorHelpers.ResponseConflictErrorHTTP{}
+// @Router /api-key/{id} [delete]
+func RevokeApiKey(c *gin.Context) {
+	dto, err := apiKeyModuleDto.CreateApiKeyIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	apiKey, err := revokeApiKey(c, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, apiKeyModuleDto.CreateDeleteApiKeyResponseDto(apiKey))
+}
@@ -0,0 +1,58 @@
+package apiKeyModule
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/logger"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"gorm.io/gorm"
+)
+
+func getApiKeys(includeRevoked bool, offset int, count int) ([]*entities.ApiKey, int64) {
+	return database.GetApiKeysAndTotal(database.ApiKeyFilter{IncludeRevoked: includeRevoked}, offset, count)
+}
+
+// createApiKey returns the key with the entity, it is the only time the key is known
+func createApiKey(c *gin.Context, name string, scopes []string, expiresInSec int64) (*entities.ApiKey, string, error) {
+	expiresAt := int64(0)
+	if expiresInSec > 0 {
+		expiresAt = timeUtil.GetUnixTime() + expiresInSec
+	}
+	apiKey, key, err := entities.CreateApiKey(name, scopes, expiresAt)
+	if err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Generate api key error. %s", err.Error()))
+		return nil, "", errorHelpers.RespondInternalError(c, "Api key can not be generated")
+	}
+	if err := database.CreateApiKey(nil, apiKey); err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Save api key error. %s", err.Error()))
+		return nil, "", errorHelpers.RespondInternalError(c, "Api key can not be stored")
+	}
+	return apiKey, key, nil
+}
+
+// revokeApiKey the key stops working right away, a revoked key stays in the list with includeRevoked
+func revokeApiKey(c *gin.Context, id int64) (*entities.ApiKey, error) {
+	var apiKey *entities.ApiKey
+	transactionError := database.DbConn.Transaction(func(tx *gorm.DB) error {
+		existingApiKey := database.GetApiKeyById(tx, id)
+		if existingApiKey == nil {
+			return errorHelpers.RespondNotFoundError(c, "Api key not found")
+		}
+		if existingApiKey.IsRevoked() {
+			return errorHelpers.RespondConflictError(c, "Api key is already revoked")
+		}
+		updateData := existingApiKey.Revoke()
+		if err := database.UpdateApiKey(tx, existingApiKey, updateData); err != nil {
+			return err
+		}
+		apiKey = existingApiKey
+		return nil
+	}, database.DefaultTxOptions)
+	if transactionError != nil {
+		return nil, transactionError
+	}
+	return apiKey, nil
+}
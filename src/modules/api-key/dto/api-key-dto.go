@@ -0,0 +1,41 @@
+package apiKeyModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+	timeUtil "go-gin-test-job/src/utils/time"
+)
+
+// ApiKeyDto the timestamps are unix seconds, null is never
+type ApiKeyDto struct {
+	Id         int64    `json:"id" example:"1"`
+	Name       string   `json:"name" example:"Billing service"`
+	Prefix     string   `json:"prefix" example:"3f9a1c2b"`
+	Scopes     []string `json:"scopes" example:"account:read,cron:run"`
+	Active     bool     `json:"active" example:"true"`
+	ExpiresAt  *int64   `json:"expiresAt" example:"1700000000"`
+	LastUsedAt *int64   `json:"lastUsedAt" example:"1600000000"`
+	RevokedAt  *int64   `json:"revokedAt" example:"1600000000"`
+	CreatedAt  int64    `json:"createdAt" example:"1600000000"`
+}
+
+func CreateApiKeyDto(apiKey *entities.ApiKey) ApiKeyDto {
+	return ApiKeyDto{
+		Id:         apiKey.Id,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.GetScopes(),
+		Active:     apiKey.IsActive(timeUtil.GetUnixTime()),
+		ExpiresAt:  getOptionalTime(apiKey.ExpiresAt),
+		LastUsedAt: getOptionalTime(apiKey.LastUsedAt),
+		RevokedAt:  getOptionalTime(apiKey.RevokedAt),
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+// getOptionalTime 0 is stored for never
+func getOptionalTime(value int64) *int64 {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
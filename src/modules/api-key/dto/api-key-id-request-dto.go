@@ -0,0 +1,55 @@
+package apiKeyModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+)
+
+type ApiKeyIdRequestDto struct {
+	Id int64 `uri:"id" json:"id" validate:"min=1" example:"1"`
+}
+
+var apiKeyIdRequestDtoValidator *validator.Validate
+
+func init() {
+	apiKeyIdRequestDtoValidator = validator.New()
+}
+
+func validateApiKeyIdRequestDto(dto *ApiKeyIdRequestDto) error {
+	return apiKeyIdRequestDtoValidator.Struct(dto)
+}
+
+// CreateApiKeyIdRequestDto is the Gin version of handling the request
+func CreateApiKeyIdRequestDto(c *gin.Context) (ApiKeyIdRequestDto, error) {
+	var dto ApiKeyIdRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		errorMessage := ApiKeyIdRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Validate the DTO
+	if err := validateApiKeyIdRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := ApiKeyIdRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func ApiKeyIdRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultFieldErrorMessage("Id")
+}
+
+func ApiKeyIdRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,9 @@
+package apiKeyModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+func CreateDeleteApiKeyResponseDto(apiKey *entities.ApiKey) ApiKeyDto {
+	return CreateApiKeyDto(apiKey)
+}
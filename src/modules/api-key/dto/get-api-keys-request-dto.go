@@ -0,0 +1,83 @@
+package apiKeyModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	stringUtil "go-gin-test-job/src/utils/string"
+)
+
+const DEFAULT_API_KEYS_COUNT = 20
+const DEFAULT_API_KEYS_OFFSET = 0
+
+type GetApiKeysRequestDto struct {
+	Offset         int  `form:"offset" json:"offset" validate:"min=0" default:"0" example:"5"`
+	Count          int  `form:"count" json:"count" validate:"min=1,max=100" default:"20" example:"20"`
+	IncludeRevoked bool `form:"includeRevoked" json:"includeRevoked" default:"false" example:"false"`
+}
+
+var getApiKeysRequestDtoValidator *validator.Validate
+
+func init() {
+	getApiKeysRequestDtoValidator = validator.New()
+}
+
+func getApiKeysRequestDtoDefaultValues(dto *GetApiKeysRequestDto) {
+	if dto.Count == 0 {
+		dto.Count = DEFAULT_API_KEYS_COUNT
+	}
+}
+
+func validateGetApiKeysRequestDto(dto *GetApiKeysRequestDto) error {
+	return getApiKeysRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetApiKeysRequestDto is the Gin version of handling the request
+func CreateGetApiKeysRequestDto(c *gin.Context) (GetApiKeysRequestDto, error) {
+	var dto GetApiKeysRequestDto
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := GetApiKeysRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	getApiKeysRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validateGetApiKeysRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetApiKeysRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func GetApiKeysRequestDtoQueryParseErrorMessage(err error) string {
+	var errorMessage string
+	if stringUtil.CaseInsensitiveContains(err.Error(), "\"offset\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".offset") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("offset")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"count\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".count") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("count")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "ParseBool") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("includeRevoked")
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
+
+func GetApiKeysRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Count" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Count" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be less than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Offset" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,24 @@
+package apiKeyModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+type GetApiKeysResponseDto struct {
+	Offset int         `json:"offset"`
+	Count  int         `json:"count"`
+	Total  int64       `json:"total"`
+	List   []ApiKeyDto `json:"list"`
+}
+
+func CreateGetApiKeysResponseDto(offset int, count int, total int64, apiKeys []*entities.ApiKey) GetApiKeysResponseDto {
+	var dto GetApiKeysResponseDto
+	dto.Offset = offset
+	dto.Count = count
+	dto.Total = total
+	dto.List = make([]ApiKeyDto, 0)
+	for _, apiKey := range apiKeys {
+		dto.List = append(dto.List, CreateApiKeyDto(apiKey))
+	}
+	return dto
+}
@@ -0,0 +1,73 @@
+package apiKeyModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/common/validations"
+	"go-gin-test-job/src/database/entities"
+	"strings"
+)
+
+// PostCreateApiKeyRequestDto expiresInSec is counted from now, 0 is a key that never expires
+type PostCreateApiKeyRequestDto struct {
+	Name         string   `json:"name" validate:"required,NotEmpty,max=64" example:"Billing service"`
+	Scopes       []string `json:"scopes" validate:"required,min=1,unique,dive,ApiKeyScopeValidation" example:"account:read,cron:run"`
+	ExpiresInSec int64    `json:"expiresInSec" validate:"min=0,max=315360000" example:"2592000"`
+}
+
+var postCreateApiKeyRequestDtoValidator *validator.Validate
+
+func init() {
+	postCreateApiKeyRequestDtoValidator = validator.New()
+	_ = postCreateApiKeyRequestDtoValidator.RegisterValidation("NotEmpty", validations.NotEmpty)
+	_ = postCreateApiKeyRequestDtoValidator.RegisterValidation("ApiKeyScopeValidation", validations.ApiKeyScopeValidation)
+}
+
+func validatePostCreateApiKeyRequestDto(dto *PostCreateApiKeyRequestDto) error {
+	return postCreateApiKeyRequestDtoValidator.Struct(dto)
+}
+
+// CreatePostCreateApiKeyRequestDto is the Gin version for handling the request
+func CreatePostCreateApiKeyRequestDto(c *gin.Context) (PostCreateApiKeyRequestDto, error) {
+	var dto PostCreateApiKeyRequestDto
+	// Parse body params into DTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errorMessage := PostCreateApiKeyRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Validate the DTO
+	if err := validatePostCreateApiKeyRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := PostCreateApiKeyRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func PostCreateApiKeyRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultQueryParseErrorMessage()
+}
+
+func PostCreateApiKeyRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Name" && (err.Tag() == "required" || err.Tag() == "NotEmpty") {
+		errorMessage = fmt.Sprintf("%s is required", err.Field())
+	} else if err.Field() == "Name" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be shorter than or equal to %s characters", err.Field(), err.Param())
+	} else if err.Field() == "Scopes" && (err.Tag() == "required" || err.Tag() == "min") {
+		errorMessage = fmt.Sprintf("%s must contain at least 1 item", err.Field())
+	} else if err.Field() == "Scopes" && err.Tag() == "unique" {
+		errorMessage = fmt.Sprintf("%s must not contain duplicates", err.Field())
+	} else if strings.HasPrefix(err.Field(), "Scopes[") && err.Tag() == "ApiKeyScopeValidation" {
+		errorMessage = fmt.Sprintf("Every scope must be one of the next values: %s", strings.Join(entities.ApiKeyScopeList, ","))
+	} else if err.Field() == "ExpiresInSec" && (err.Tag() == "min" || err.Tag() == "max") {
+		errorMessage = fmt.Sprintf("%s must be between 0 and 315360000", err.Field())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
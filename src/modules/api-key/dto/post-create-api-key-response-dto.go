@@ -0,0 +1,18 @@
+package apiKeyModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+// PostCreateApiKeyResponseDto key is returned only here, only its hash is stored and it can not be shown again
+type PostCreateApiKeyResponseDto struct {
+	ApiKeyDto
+	Key string `json:"key" example:"3f9a1c2b5d7e4f60a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718"`
+}
+
+func CreatePostCreateApiKeyResponseDto(apiKey *entities.ApiKey, key string) PostCreateApiKeyResponseDto {
+	return PostCreateApiKeyResponseDto{
+		ApiKeyDto: CreateApiKeyDto(apiKey),
+		Key:       key,
+	}
+}
@@ -0,0 +1,212 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const apiErrorBodyMaxLength = 256
+
+var ErrEmptyResponse = errors.New("Empty response")
+
+// HttpStatusError is returned for a non-2xx response. The body is cut so that an html error page does not flood the logs
+type HttpStatusError struct {
+	Url        string
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("Request %s failed with status %d. %s", e.Url, e.StatusCode, e.Body)
+}
+
+// IsRetryable is true for 429 and 5xx, any other status will not change on a retry
+func (e *HttpStatusError) IsRetryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// InvalidResponseError is returned for a 2xx response that can not be used as a balance
+type InvalidResponseError struct {
+	Url    string
+	Reason string
+}
+
+func (e *InvalidResponseError) Error() string {
+	return fmt.Sprintf("Request %s returned an invalid response. %s", e.Url, e.Reason)
+}
+
+// ApiClientOptions are the limits of one api. RequestsPerSec 0 disables the rate limiter, MaxRetries 0 disables the retries
+type ApiClientOptions struct {
+	RequestsPerSec int
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// ApiClient gets json from an external api with rate limiting and retries. The http client is shared by all the calls
+type ApiClient struct {
+	client  *http.Client
+	options ApiClientOptions
+	limiter *rateLimiter
+}
+
+func NewApiClient(client *http.Client, options ApiClientOptions) *ApiClient {
+	return &ApiClient{
+		client:  client,
+		options: options,
+		limiter: newRateLimiter(options.RequestsPerSec),
+	}
+}
+
+// GetJson decodes the response into target, retrying 429, 5xx and network errors with backoff
+func (c *ApiClient) GetJson(ctx context.Context, url string, target interface{}) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = c.getJson(ctx, url, target)
+		if err == nil || attempt >= c.options.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+		delay := c.getRetryDelay(attempt)
+		var statusErr *HttpStatusError
+		if errors.As(err, &statusErr) {
+			if !statusErr.IsRetryable() {
+				return err
+			}
+			if statusErr.RetryAfter > c.options.RetryMaxDelay {
+				return err
+			}
+			if statusErr.RetryAfter > delay {
+				delay = statusErr.RetryAfter
+			}
+		} else if !isNetworkError(err) {
+			return err
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *ApiClient) getJson(ctx context.Context, url string, target interface{}) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, apiErrorBodyMaxLength))
+		return &HttpStatusError{
+			Url:        url,
+			StatusCode: response.StatusCode,
+			RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+			Body:       strings.TrimSpace(string(body)),
+		}
+	}
+	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &InvalidResponseError{Url: url, Reason: ErrEmptyResponse.Error()}
+		}
+		return &InvalidResponseError{Url: url, Reason: err.Error()}
+	}
+	return nil
+}
+
+// getRetryDelay is the full jitter backoff: a random delay up to base * 2^attempt, capped by the max delay
+func (c *ApiClient) getRetryDelay(attempt int) time.Duration {
+	delay := c.options.RetryBaseDelay << attempt
+	if delay <= 0 || delay > c.options.RetryMaxDelay {
+		delay = c.options.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// sleepContext returns the context error if the context is done before the delay ends
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isNetworkError(err error) bool {
+	var statusErr *HttpStatusError
+	var invalidErr *InvalidResponseError
+	return !errors.As(err, &statusErr) && !errors.As(err, &invalidErr)
+}
+
+// parseRetryAfter reads the header as seconds or as an http date, 0 means there is no delay
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// rateLimiter spaces the requests evenly, it is safe for concurrent use
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSec int) *rateLimiter {
+	limiter := &rateLimiter{}
+	if requestsPerSec > 0 {
+		limiter.interval = time.Second / time.Duration(requestsPerSec)
+	}
+	return limiter
+}
+
+// Wait takes the slot even if the context is done meanwhile, the slot is not given back
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	return sleepContext(ctx, wait)
+}
@@ -0,0 +1,168 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-gin-test-job/src/logger"
+	"strings"
+)
+
+var ErrNoBalanceProvider = errors.New("No balance provider answered")
+var ErrBalanceQuorumNotReached = errors.New("Balance quorum is not reached")
+
+// ProviderBalance is the answer of one provider, Err is set instead of Balance when the request failed
+type ProviderBalance struct {
+	Provider string
+	Balance  AddressBalance
+	Err      error
+}
+
+// BalanceResult is the resolved balance. Source holds the names of the providers that returned it
+type BalanceResult struct {
+	Balance   AddressBalance
+	Source    string
+	Answers   []ProviderBalance
+	Disagreed bool
+}
+
+// BalanceResolver queries the providers in priority order, with failover or with a quorum
+type BalanceResolver struct {
+	providers []BalanceProvider
+	quorum    int
+}
+
+func NewBalanceResolver(providers []BalanceProvider, quorum int) (*BalanceResolver, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("At least one balance provider is required")
+	}
+	if quorum < 0 || quorum > len(providers) {
+		return nil, fmt.Errorf("Balance quorum must be between 0 and %d", len(providers))
+	}
+	return &BalanceResolver{providers: providers, quorum: quorum}, nil
+}
+
+func (r *BalanceResolver) IsQuorumMode() bool {
+	return r.quorum > 1
+}
+
+// GetAddressBalance returns the result with the answers even on error so that disagreements can be reported
+func (r *BalanceResolver) GetAddressBalance(ctx context.Context, address string) (BalanceResult, error) {
+	if r.IsQuorumMode() {
+		return r.getQuorumBalance(ctx, address)
+	}
+	return r.getFailoverBalance(ctx, address)
+}
+
+func (r *BalanceResolver) getFailoverBalance(ctx context.Context, address string) (BalanceResult, error) {
+	var result BalanceResult
+	for _, provider := range r.providers {
+		balance, err := provider.GetAddressBalance(ctx, address)
+		result.Answers = append(result.Answers, ProviderBalance{Provider: provider.Name(), Balance: balance, Err: err})
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, err
+			}
+			logger.Logger.Warn().Msg(fmt.Sprintf("Balance provider %s address %s error, trying the next provider. %s", provider.Name(), address, err.Error()))
+			continue
+		}
+		result.Balance = balance
+		result.Source = provider.Name()
+		return result, nil
+	}
+	return result, ErrNoBalanceProvider
+}
+
+func (r *BalanceResolver) getQuorumBalance(ctx context.Context, address string) (BalanceResult, error) {
+	var result BalanceResult
+	for _, provider := range r.providers {
+		balance, err := provider.GetAddressBalance(ctx, address)
+		result.Answers = append(result.Answers, ProviderBalance{Provider: provider.Name(), Balance: balance, Err: err})
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, err
+			}
+			logger.Logger.Warn().Msg(fmt.Sprintf("Balance provider %s address %s error. %s", provider.Name(), address, err.Error()))
+		}
+	}
+	// Group the answers by the confirmed balance, the providers of the biggest group win
+	var bestSources []string
+	for _, answer := range result.Answers {
+		if answer.Err != nil {
+			continue
+		}
+		sources := make([]string, 0)
+		for _, other := range result.Answers {
+			if other.Err != nil {
+				continue
+			}
+			if other.Balance.Confirmed.Equal(answer.Balance.Confirmed) {
+				sources = append(sources, other.Provider)
+			} else {
+				result.Disagreed = true
+			}
+		}
+		if len(sources) > len(bestSources) {
+			bestSources = sources
+			result.Balance = answer.Balance
+		}
+	}
+	if result.Disagreed {
+		logger.Logger.Warn().Msg(fmt.Sprintf("Balance providers disagree on address %s. %s", address, FormatProviderBalances(result.Answers)))
+	}
+	if len(bestSources) < r.quorum {
+		return result, ErrBalanceQuorumNotReached
+	}
+	result.Source = strings.Join(bestSources, ",")
+	return result, nil
+}
+
+// GetAddressTransactions takes the transactions from the first provider that answers
+func (r *BalanceResolver) GetAddressTransactions(ctx context.Context, address string, fromHeight int64, save SaveAddressTransactions) error {
+	var saveErr error
+	saveTransactions := func(transactions []AddressTransaction) error {
+		saveErr = save(transactions)
+		return saveErr
+	}
+	for _, provider := range r.providers {
+		err := provider.GetAddressTransactions(ctx, address, fromHeight, saveTransactions)
+		if err != nil {
+			if ctx.Err() != nil || saveErr != nil {
+				return err
+			}
+			logger.Logger.Warn().Msg(fmt.Sprintf("Transaction provider %s address %s error, trying the next provider. %s", provider.Name(), address, err.Error()))
+			continue
+		}
+		return nil
+	}
+	return ErrNoBalanceProvider
+}
+
+// GetAddressUtxos takes the unspent outputs from the first provider that answers
+func (r *BalanceResolver) GetAddressUtxos(ctx context.Context, address string) ([]AddressUtxo, error) {
+	for _, provider := range r.providers {
+		utxos, err := provider.GetAddressUtxos(ctx, address)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			logger.Logger.Warn().Msg(fmt.Sprintf("Utxo provider %s address %s error, trying the next provider. %s", provider.Name(), address, err.Error()))
+			continue
+		}
+		return utxos, nil
+	}
+	return nil, ErrNoBalanceProvider
+}
+
+// FormatProviderBalances formats the answers as "provider: balance" for the logs
+func FormatProviderBalances(answers []ProviderBalance) string {
+	parts := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		if answer.Err != nil {
+			parts = append(parts, fmt.Sprintf("%s: error %s", answer.Provider, answer.Err.Error()))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s: %s", answer.Provider, answer.Balance.Confirmed.String()))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
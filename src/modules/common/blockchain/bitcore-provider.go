@@ -0,0 +1,329 @@
+package blockchain
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	currencyUtil "go-gin-test-job/src/utils/currency"
+	"slices"
+	"time"
+)
+
+const bitcoreDefaultUrl = "https://api.bitcore.io/api"
+
+type BitcoreBalanceResponse struct {
+	Confirmed   *int64 `json:"confirmed"`
+	Unconfirmed *int64 `json:"unconfirmed"`
+	Balance     *int64 `json:"balance"`
+}
+
+// bitcoreCoinsPageSize is the count of the coins requested in one page of the Bitcore api
+const bitcoreCoinsPageSize = 1000
+
+// bitcoreTransactionsPageSize is the count of the transactions read one by one and saved together
+const bitcoreTransactionsPageSize = 25
+
+// bitcoreMempoolHeight every negative height of a coin is the mempool
+const bitcoreMempoolHeight = -1
+
+// The heights the coins of an address are paged by, from the oldest block
+const (
+	bitcoreMintHeightPaging  = "mintHeight"
+	bitcoreSpentHeightPaging = "spentHeight"
+)
+
+// BitcoreCoin is an output of the address, the heights are negative in the mempool
+type BitcoreCoin struct {
+	Id          string `json:"_id"`
+	MintTxid    string `json:"mintTxid"`
+	MintIndex   int64  `json:"mintIndex"`
+	MintHeight  int64  `json:"mintHeight"`
+	SpentTxid   string `json:"spentTxid"`
+	SpentHeight int64  `json:"spentHeight"`
+	Value       int64  `json:"value"`
+}
+
+type BitcoreTransactionResponse struct {
+	Txid                string `json:"txid"`
+	BlockHeight         int64  `json:"blockHeight"`
+	BlockTimeNormalized string `json:"blockTimeNormalized"`
+	Fee                 int64  `json:"fee"`
+	Confirmations       int64  `json:"confirmations"`
+}
+
+type BitcoreBlockResponse struct {
+	Height *int64 `json:"height"`
+}
+
+// BitcoreProvider reads balances and transactions from the Bitcore node api
+type BitcoreProvider struct {
+	client             *ApiClient
+	baseUrl            string
+	transactionsPerRun int
+}
+
+func NewBitcoreProvider(client *ApiClient, url string, network string, transactionsPerRun int) *BitcoreProvider {
+	if url == "" {
+		url = bitcoreDefaultUrl
+	}
+	return &BitcoreProvider{
+		client:             client,
+		baseUrl:            fmt.Sprintf("%s/BTC/%s", url, network),
+		transactionsPerRun: transactionsPerRun,
+	}
+}
+
+func (p *BitcoreProvider) Name() string {
+	return BitcoreProviderName
+}
+
+func (p *BitcoreProvider) GetAddressBalance(ctx context.Context, address string) (AddressBalance, error) {
+	var balance AddressBalance
+	url := fmt.Sprintf("%s/address/%s/balance", p.baseUrl, address)
+	var responseData BitcoreBalanceResponse
+	if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+		return balance, err
+	}
+	// A missing field must not be read as a zero balance
+	if responseData.Confirmed == nil {
+		return balance, &InvalidResponseError{Url: url, Reason: "Confirmed balance is missing"}
+	}
+	if *responseData.Confirmed < 0 {
+		return balance, &InvalidResponseError{Url: url, Reason: "Confirmed balance is negative"}
+	}
+	// Old Bitcore versions do not return the unconfirmed balance, then there are no pending funds
+	unconfirmed := int64(0)
+	if responseData.Unconfirmed != nil {
+		unconfirmed = *responseData.Unconfirmed
+	}
+	if responseData.Balance != nil && *responseData.Balance != *responseData.Confirmed+unconfirmed {
+		return balance, &InvalidResponseError{Url: url, Reason: "Balance is not the sum of the confirmed and the unconfirmed balances"}
+	}
+	balance = CreateAddressBalance(currencyUtil.FromSatoshi(*responseData.Confirmed), currencyUtil.FromSatoshi(unconfirmed))
+	return balance, nil
+}
+
+// GetAddressTransactions reads the transactions of the coins minted or spent from fromHeight, oldest block first
+func (p *BitcoreProvider) GetAddressTransactions(ctx context.Context, address string, fromHeight int64, save SaveAddressTransactions) error {
+	mintedCoins, err := p.getAddressCoinsFrom(ctx, address, bitcoreMintHeightPaging, fromHeight)
+	if err != nil {
+		return err
+	}
+	spentCoins, err := p.getAddressCoinsFrom(ctx, address, bitcoreSpentHeightPaging, fromHeight)
+	if err != nil {
+		return err
+	}
+	// The coins minted or spent in the mempool are not paged by a block, they are among the unspent coins
+	unspentCoins, err := p.getAddressUnspentCoins(ctx, address)
+	if err != nil {
+		return err
+	}
+	// Every coin is received by the transaction that minted it and spent by the one that spent it
+	txids := make([]string, 0)
+	amounts := make(map[string]*bitcoreTransactionAmount)
+	getAmount := func(txid string, height int64) *bitcoreTransactionAmount {
+		amount, exists := amounts[txid]
+		if !exists {
+			amount = &bitcoreTransactionAmount{height: max(height, bitcoreMempoolHeight)}
+			amounts[txid] = amount
+			txids = append(txids, txid)
+		}
+		return amount
+	}
+	for _, coin := range mintedCoins {
+		if coin.MintHeight >= fromHeight {
+			getAmount(coin.MintTxid, coin.MintHeight).in += coin.Value
+		}
+	}
+	for _, coin := range spentCoins {
+		if coin.SpentTxid != "" && coin.SpentHeight >= fromHeight {
+			getAmount(coin.SpentTxid, coin.SpentHeight).out += coin.Value
+		}
+	}
+	for _, coin := range unspentCoins {
+		if coin.MintHeight < 0 {
+			getAmount(coin.MintTxid, coin.MintHeight).in += coin.Value
+		}
+		if coin.SpentTxid != "" && coin.SpentHeight < 0 {
+			getAmount(coin.SpentTxid, coin.SpentHeight).out += coin.Value
+		}
+	}
+	slices.SortStableFunc(txids, func(a string, b string) int {
+		return compareBitcoreHeights(amounts[a].height, amounts[b].height)
+	})
+	transactions := make([]AddressTransaction, 0, bitcoreTransactionsPageSize)
+	for index, txid := range txids {
+		// A block is always read to the end, the block of fromHeight is read again by every call
+		if p.transactionsPerRun > 0 && index >= p.transactionsPerRun {
+			previousHeight := amounts[txids[index-1]].height
+			if amounts[txid].height != previousHeight && previousHeight > fromHeight {
+				break
+			}
+		}
+		transaction, err := p.getTransaction(ctx, txid)
+		if err != nil {
+			return err
+		}
+		transaction.AmountIn = currencyUtil.FromSatoshi(amounts[txid].in)
+		transaction.AmountOut = currencyUtil.FromSatoshi(amounts[txid].out)
+		transactions = append(transactions, transaction)
+		if len(transactions) == bitcoreTransactionsPageSize {
+			if err := save(transactions); err != nil {
+				return err
+			}
+			transactions = make([]AddressTransaction, 0, bitcoreTransactionsPageSize)
+		}
+	}
+	if len(transactions) == 0 {
+		return nil
+	}
+	return save(transactions)
+}
+
+// bitcoreTransactionAmount height is the block of the transaction or bitcoreMempoolHeight
+type bitcoreTransactionAmount struct {
+	height int64
+	in     int64
+	out    int64
+}
+
+// compareBitcoreHeights orders the blocks from the oldest one and the mempool after them
+func compareBitcoreHeights(a int64, b int64) int {
+	if (a < 0) != (b < 0) {
+		if a < 0 {
+			return 1
+		}
+		return -1
+	}
+	return cmp.Compare(a, b)
+}
+
+// getAddressCoinsFrom reads the coins of the address minted or spent from fromHeight, as paging tells
+func (p *BitcoreProvider) getAddressCoinsFrom(ctx context.Context, address string, paging string, fromHeight int64) ([]BitcoreCoin, error) {
+	coins := make([]BitcoreCoin, 0)
+	read := make(map[string]bool)
+	since := fromHeight - 1
+	for {
+		url := fmt.Sprintf("%s/address/%s/txs?limit=%d&paging=%s&direction=1&since=%d", p.baseUrl, address, bitcoreCoinsPageSize, paging, since)
+		var responseData []BitcoreCoin
+		if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+			return nil, err
+		}
+		for _, coin := range responseData {
+			if coin.MintTxid == "" {
+				return nil, &InvalidResponseError{Url: url, Reason: "Coin transaction id is missing"}
+			}
+			if !read[coin.Id] {
+				read[coin.Id] = true
+				coins = append(coins, coin)
+			}
+		}
+		if len(responseData) < bitcoreCoinsPageSize {
+			return coins, nil
+		}
+		lastHeight := responseData[len(responseData)-1].MintHeight
+		if paging == bitcoreSpentHeightPaging {
+			lastHeight = responseData[len(responseData)-1].SpentHeight
+		}
+		// The next page can not start inside a block that fills a whole page
+		if lastHeight-1 <= since {
+			return nil, &InvalidResponseError{Url: url, Reason: "Coins of one block do not fit in a page"}
+		}
+		since = lastHeight - 1
+	}
+}
+
+// getAddressUnspentCoins reads the unspent coins of the address, with the ones spent in the mempool
+func (p *BitcoreProvider) getAddressUnspentCoins(ctx context.Context, address string) ([]BitcoreCoin, error) {
+	coins := make([]BitcoreCoin, 0)
+	since := ""
+	for {
+		url := fmt.Sprintf("%s/address/%s?unspent=true&limit=%d", p.baseUrl, address, bitcoreCoinsPageSize)
+		if since != "" {
+			url += "&since=" + since
+		}
+		var responseData []BitcoreCoin
+		if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+			return nil, err
+		}
+		for _, coin := range responseData {
+			if coin.MintTxid == "" {
+				return nil, &InvalidResponseError{Url: url, Reason: "Coin transaction id is missing"}
+			}
+		}
+		coins = append(coins, responseData...)
+		if len(responseData) < bitcoreCoinsPageSize {
+			return coins, nil
+		}
+		since = responseData[len(responseData)-1].Id
+	}
+}
+
+func (p *BitcoreProvider) getTransaction(ctx context.Context, txid string) (AddressTransaction, error) {
+	var transaction AddressTransaction
+	url := fmt.Sprintf("%s/tx/%s", p.baseUrl, txid)
+	var responseData BitcoreTransactionResponse
+	if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+		return transaction, err
+	}
+	if responseData.Txid != txid {
+		return transaction, &InvalidResponseError{Url: url, Reason: "Transaction id does not match"}
+	}
+	transaction.Txid = txid
+	transaction.Fee = currencyUtil.FromSatoshi(responseData.Fee)
+	if responseData.BlockHeight >= 0 {
+		blockTime, err := time.Parse(time.RFC3339, responseData.BlockTimeNormalized)
+		if err != nil {
+			return transaction, &InvalidResponseError{Url: url, Reason: "Block time is invalid"}
+		}
+		blockHeight := responseData.BlockHeight
+		blockTimeUnix := blockTime.Unix()
+		transaction.BlockHeight = &blockHeight
+		transaction.BlockTime = &blockTimeUnix
+		transaction.Confirmations = responseData.Confirmations
+	}
+	return transaction, nil
+}
+
+func (p *BitcoreProvider) GetAddressUtxos(ctx context.Context, address string) ([]AddressUtxo, error) {
+	tipHeight, err := p.getTipHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	coins, err := p.getAddressUnspentCoins(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	scriptType := GetAddressScriptType(address)
+	utxos := make([]AddressUtxo, 0, len(coins))
+	for _, coin := range coins {
+		// A coin spent in the mempool is not available any more
+		if coin.SpentTxid != "" {
+			continue
+		}
+		confirmations := int64(0)
+		if coin.MintHeight >= 0 {
+			confirmations = tipHeight - coin.MintHeight + 1
+		}
+		utxos = append(utxos, AddressUtxo{
+			Txid:          coin.MintTxid,
+			Vout:          coin.MintIndex,
+			Value:         currencyUtil.FromSatoshi(coin.Value),
+			Confirmations: confirmations,
+			ScriptType:    scriptType,
+		})
+	}
+	return utxos, nil
+}
+
+func (p *BitcoreProvider) getTipHeight(ctx context.Context) (int64, error) {
+	url := fmt.Sprintf("%s/block/tip", p.baseUrl)
+	var responseData BitcoreBlockResponse
+	if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+		return 0, err
+	}
+	if responseData.Height == nil {
+		return 0, &InvalidResponseError{Url: url, Reason: "Block height is missing"}
+	}
+	return *responseData.Height, nil
+}
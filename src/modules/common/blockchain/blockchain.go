@@ -1,36 +1,154 @@
 package blockchain
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"github.com/shopspring/decimal"
 	"go-gin-test-job/src/config"
-	currencyUtil "go-gin-test-job/src/utils/currency"
 	timeUtil "go-gin-test-job/src/utils/time"
 	"net/http"
+	"strings"
 )
 
-var externalUrl = "https://api.bitcore.io/api/BTC/mainnet"
+const (
+	BitcoreProviderName = "bitcore"
+	EsploraProviderName = "esplora"
+)
+
+const (
+	MainnetNetwork = "mainnet"
+	TestnetNetwork = "testnet"
+)
 
-type BlockchainBalanceResponse struct {
-	Confirmed int64 `json:"confirmed"`
+// AddressBalance is the balance of an address. Unconfirmed is the mempool delta, it is negative for pending outgoing funds
+type AddressBalance struct {
+	Confirmed   decimal.Decimal
+	Unconfirmed decimal.Decimal
+	Total       decimal.Decimal
 }
 
-func GetAddressBalance(address string) (decimal.Decimal, error) {
-	balance := decimal.NewFromInt(0)
-	url := fmt.Sprintf("%s/address/%s/balance", externalUrl, address)
+func CreateAddressBalance(confirmed decimal.Decimal, unconfirmed decimal.Decimal) AddressBalance {
+	return AddressBalance{
+		Confirmed:   confirmed,
+		Unconfirmed: unconfirmed,
+		Total:       confirmed.Add(unconfirmed),
+	}
+}
+
+// AddressTransaction is a transaction of an address, the block is nil while it is in the mempool
+type AddressTransaction struct {
+	Txid          string
+	BlockHeight   *int64
+	BlockTime     *int64
+	AmountIn      decimal.Decimal
+	AmountOut     decimal.Decimal
+	Fee           decimal.Decimal
+	Confirmations int64
+}
+
+// AddressUtxo is an unspent output of an address. Confirmations is 0 while the output is in the mempool
+type AddressUtxo struct {
+	Txid          string
+	Vout          int64
+	Value         decimal.Decimal
+	Confirmations int64
+	ScriptType    string
+}
+
+const (
+	ScriptTypeP2PKH   = "p2pkh"
+	ScriptTypeP2SH    = "p2sh"
+	ScriptTypeP2WPKH  = "p2wpkh"
+	ScriptTypeP2WSH   = "p2wsh"
+	ScriptTypeP2TR    = "p2tr"
+	ScriptTypeUnknown = "unknown"
+)
+
+// GetAddressScriptType detects the output script type by the address format, every output of an address has the same type
+func GetAddressScriptType(address string) string {
+	lowerAddress := strings.ToLower(address)
+	for _, prefix := range []string{"bc1", "tb1"} {
+		if !strings.HasPrefix(lowerAddress, prefix) {
+			continue
+		}
+		witness := strings.TrimPrefix(lowerAddress, prefix)
+		switch {
+		case strings.HasPrefix(witness, "p"):
+			return ScriptTypeP2TR
+		case strings.HasPrefix(witness, "q") && len(address) == len(prefix)+39:
+			return ScriptTypeP2WPKH
+		case strings.HasPrefix(witness, "q") && len(address) == len(prefix)+59:
+			return ScriptTypeP2WSH
+		}
+		return ScriptTypeUnknown
+	}
+	if address == "" {
+		return ScriptTypeUnknown
+	}
+	switch address[0] {
+	case '1', 'm', 'n':
+		return ScriptTypeP2PKH
+	case '3', '2':
+		return ScriptTypeP2SH
+	}
+	return ScriptTypeUnknown
+}
+
+// SaveAddressTransactions stores a page of the transactions of an address
+type SaveAddressTransactions func(transactions []AddressTransaction) error
+
+// BalanceProvider gets the balance and the transactions of an address from an external blockchain api. The requests stop when the context is done
+type BalanceProvider interface {
+	// Name is stored as the source of the balance history
+	Name() string
+	GetAddressBalance(ctx context.Context, address string) (AddressBalance, error)
+	// GetAddressTransactions saves the mempool transactions and the confirmed ones at or above fromHeight
+	GetAddressTransactions(ctx context.Context, address string, fromHeight int64, save SaveAddressTransactions) error
+	GetAddressUtxos(ctx context.Context, address string) ([]AddressUtxo, error)
+}
+
+// Resolver queries the balance providers chosen by the config
+var Resolver *BalanceResolver
+
+func InitBalanceResolver() error {
+	blockchainConfig := config.AppConfig.Blockchain
+	// One http client for all the providers so that the connections are reused
 	client := &http.Client{
 		Timeout: timeUtil.DurationSeconds(config.AppConfig.RequestTimeoutSec),
 	}
-	response, err := client.Get(url)
+	providers := make([]BalanceProvider, 0, len(blockchainConfig.Providers))
+	for _, providerConfig := range blockchainConfig.Providers {
+		provider, err := NewBalanceProvider(client, providerConfig, blockchainConfig)
+		if err != nil {
+			return err
+		}
+		providers = append(providers, provider)
+	}
+	resolver, err := NewBalanceResolver(providers, blockchainConfig.Quorum)
 	if err != nil {
-		return balance, err
+		return err
+	}
+	Resolver = resolver
+	return nil
+}
+
+// NewBalanceProvider creates the provider with its own rate limiter, the limits of an api do not depend on the other providers
+func NewBalanceProvider(client *http.Client, providerConfig config.BlockchainProviderConfig, blockchainConfig config.BlockchainConfig) (BalanceProvider, error) {
+	network := blockchainConfig.Network
+	if network != MainnetNetwork && network != TestnetNetwork {
+		return nil, fmt.Errorf("Unknown blockchain network %s", network)
 	}
-	defer response.Body.Close()
-	var responseData BlockchainBalanceResponse
-	if err := json.NewDecoder(response.Body).Decode(&responseData); err != nil {
-		return balance, err
+	apiClient := NewApiClient(client, ApiClientOptions{
+		RequestsPerSec: blockchainConfig.RequestsPerSec,
+		MaxRetries:     blockchainConfig.MaxRetries,
+		RetryBaseDelay: timeUtil.DurationMillis(blockchainConfig.RetryBaseDelayMs),
+		RetryMaxDelay:  timeUtil.DurationMillis(blockchainConfig.RetryMaxDelayMs),
+	})
+	switch providerConfig.Name {
+	case BitcoreProviderName:
+		return NewBitcoreProvider(apiClient, providerConfig.Url, network, blockchainConfig.TransactionsPerRun), nil
+	case EsploraProviderName:
+		return NewEsploraProvider(apiClient, providerConfig.Url, network), nil
 	}
-	balance = currencyUtil.FromSatoshi(responseData.Confirmed)
-	return balance, nil
+	return nil, fmt.Errorf("Unknown blockchain provider %s", providerConfig.Name)
 }
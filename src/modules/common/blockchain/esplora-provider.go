@@ -0,0 +1,211 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	currencyUtil "go-gin-test-job/src/utils/currency"
+)
+
+var esploraDefaultUrls = map[string]string{
+	MainnetNetwork: "https://blockstream.info/api",
+	TestnetNetwork: "https://blockstream.info/testnet/api",
+}
+
+type EsploraAddressStats struct {
+	FundedTxoSum *int64 `json:"funded_txo_sum"`
+	SpentTxoSum  *int64 `json:"spent_txo_sum"`
+}
+
+type EsploraAddressResponse struct {
+	ChainStats   *EsploraAddressStats `json:"chain_stats"`
+	MempoolStats *EsploraAddressStats `json:"mempool_stats"`
+}
+
+// esploraTransactionsPageSize is the count of the confirmed transactions in one page of the Esplora api
+const esploraTransactionsPageSize = 25
+
+type EsploraTransactionStatus struct {
+	Confirmed   bool   `json:"confirmed"`
+	BlockHeight *int64 `json:"block_height"`
+	BlockTime   *int64 `json:"block_time"`
+}
+
+type EsploraTransactionOutput struct {
+	ScriptpubkeyAddress string `json:"scriptpubkey_address"`
+	Value               int64  `json:"value"`
+}
+
+// EsploraTransactionInput prevout is nil for a coinbase input
+type EsploraTransactionInput struct {
+	Prevout *EsploraTransactionOutput `json:"prevout"`
+}
+
+type EsploraTransactionResponse struct {
+	Txid   string                     `json:"txid"`
+	Fee    int64                      `json:"fee"`
+	Status EsploraTransactionStatus   `json:"status"`
+	Vin    []EsploraTransactionInput  `json:"vin"`
+	Vout   []EsploraTransactionOutput `json:"vout"`
+}
+
+type EsploraUtxoResponse struct {
+	Txid   string                   `json:"txid"`
+	Vout   *int64                   `json:"vout"`
+	Value  *int64                   `json:"value"`
+	Status EsploraTransactionStatus `json:"status"`
+}
+
+// EsploraProvider reads balances from an Esplora api such as Blockstream or mempool.space
+type EsploraProvider struct {
+	client  *ApiClient
+	baseUrl string
+}
+
+func NewEsploraProvider(client *ApiClient, url string, network string) *EsploraProvider {
+	if url == "" {
+		url = esploraDefaultUrls[network]
+	}
+	return &EsploraProvider{
+		client:  client,
+		baseUrl: url,
+	}
+}
+
+func (p *EsploraProvider) Name() string {
+	return EsploraProviderName
+}
+
+func (p *EsploraProvider) GetAddressBalance(ctx context.Context, address string) (AddressBalance, error) {
+	var balance AddressBalance
+	url := fmt.Sprintf("%s/address/%s", p.baseUrl, address)
+	var responseData EsploraAddressResponse
+	if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+		return balance, err
+	}
+	// A missing field must not be read as a zero balance
+	chainStats := responseData.ChainStats
+	if chainStats == nil || chainStats.FundedTxoSum == nil || chainStats.SpentTxoSum == nil {
+		return balance, &InvalidResponseError{Url: url, Reason: "Chain stats are missing"}
+	}
+	confirmed := *chainStats.FundedTxoSum - *chainStats.SpentTxoSum
+	if confirmed < 0 {
+		return balance, &InvalidResponseError{Url: url, Reason: "Confirmed balance is negative"}
+	}
+	// The mempool stats are the unconfirmed transactions, the same as the unconfirmed balance of Bitcore
+	unconfirmed := int64(0)
+	if mempoolStats := responseData.MempoolStats; mempoolStats != nil && mempoolStats.FundedTxoSum != nil && mempoolStats.SpentTxoSum != nil {
+		unconfirmed = *mempoolStats.FundedTxoSum - *mempoolStats.SpentTxoSum
+	}
+	balance = CreateAddressBalance(currencyUtil.FromSatoshi(confirmed), currencyUtil.FromSatoshi(unconfirmed))
+	return balance, nil
+}
+
+// GetAddressTransactions saves the pages together, they come newest first
+func (p *EsploraProvider) GetAddressTransactions(ctx context.Context, address string, fromHeight int64, save SaveAddressTransactions) error {
+	transactions, err := p.getAddressTransactions(ctx, address, fromHeight)
+	if err != nil {
+		return err
+	}
+	return save(transactions)
+}
+
+// getAddressTransactions pages the address transactions newest first until a block below fromHeight
+func (p *EsploraProvider) getAddressTransactions(ctx context.Context, address string, fromHeight int64) ([]AddressTransaction, error) {
+	tipHeight, err := p.getTipHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]AddressTransaction, 0)
+	url := fmt.Sprintf("%s/address/%s/txs", p.baseUrl, address)
+	for {
+		var responseData []EsploraTransactionResponse
+		if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+			return nil, err
+		}
+		confirmedCount := 0
+		lastTxid := ""
+		for _, transaction := range responseData {
+			if transaction.Txid == "" {
+				return nil, &InvalidResponseError{Url: url, Reason: "Transaction id is missing"}
+			}
+			if transaction.Status.Confirmed {
+				if transaction.Status.BlockHeight == nil {
+					return nil, &InvalidResponseError{Url: url, Reason: "Block height is missing"}
+				}
+				confirmedCount++
+				lastTxid = transaction.Txid
+				if *transaction.Status.BlockHeight < fromHeight {
+					return transactions, nil
+				}
+			}
+			transactions = append(transactions, createEsploraAddressTransaction(address, transaction, tipHeight))
+		}
+		if confirmedCount < esploraTransactionsPageSize {
+			return transactions, nil
+		}
+		url = fmt.Sprintf("%s/address/%s/txs/chain/%s", p.baseUrl, address, lastTxid)
+	}
+}
+
+func createEsploraAddressTransaction(address string, transaction EsploraTransactionResponse, tipHeight int64) AddressTransaction {
+	var amountIn, amountOut int64
+	for _, output := range transaction.Vout {
+		if output.ScriptpubkeyAddress == address {
+			amountIn += output.Value
+		}
+	}
+	for _, input := range transaction.Vin {
+		if input.Prevout != nil && input.Prevout.ScriptpubkeyAddress == address {
+			amountOut += input.Prevout.Value
+		}
+	}
+	addressTransaction := AddressTransaction{
+		Txid:      transaction.Txid,
+		AmountIn:  currencyUtil.FromSatoshi(amountIn),
+		AmountOut: currencyUtil.FromSatoshi(amountOut),
+		Fee:       currencyUtil.FromSatoshi(transaction.Fee),
+	}
+	if transaction.Status.Confirmed {
+		addressTransaction.BlockHeight = transaction.Status.BlockHeight
+		addressTransaction.BlockTime = transaction.Status.BlockTime
+		addressTransaction.Confirmations = tipHeight - *transaction.Status.BlockHeight + 1
+	}
+	return addressTransaction
+}
+
+func (p *EsploraProvider) GetAddressUtxos(ctx context.Context, address string) ([]AddressUtxo, error) {
+	tipHeight, err := p.getTipHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/address/%s/utxo", p.baseUrl, address)
+	var responseData []EsploraUtxoResponse
+	if err := p.client.GetJson(ctx, url, &responseData); err != nil {
+		return nil, err
+	}
+	scriptType := GetAddressScriptType(address)
+	utxos := make([]AddressUtxo, 0, len(responseData))
+	for _, utxo := range responseData {
+		if utxo.Txid == "" || utxo.Vout == nil || utxo.Value == nil {
+			return nil, &InvalidResponseError{Url: url, Reason: "Utxo is incomplete"}
+		}
+		confirmations := int64(0)
+		if utxo.Status.Confirmed && utxo.Status.BlockHeight != nil {
+			confirmations = tipHeight - *utxo.Status.BlockHeight + 1
+		}
+		utxos = append(utxos, AddressUtxo{
+			Txid:          utxo.Txid,
+			Vout:          *utxo.Vout,
+			Value:         currencyUtil.FromSatoshi(*utxo.Value),
+			Confirmations: confirmations,
+			ScriptType:    scriptType,
+		})
+	}
+	return utxos, nil
+}
+
+func (p *EsploraProvider) getTipHeight(ctx context.Context) (int64, error) {
+	var tipHeight int64
+	err := p.client.GetJson(ctx, fmt.Sprintf("%s/blocks/tip/height", p.baseUrl), &tipHeight)
+	return tipHeight, err
+}
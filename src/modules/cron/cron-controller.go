@@ -2,20 +2,177 @@ package cronModule
 
 import (
 	"github.com/gin-gonic/gin"
-	"go-gin-test-job/src/common/dto"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
 )
 
 // UpdateAccountsBalances Update accounts balances
 // @Summary Update accounts balances
-// @Description Update accounts balances
+// @Description Update balances of the accounts due for a refresh, the longest overdue first, and return the report of the run, it is also stored in the run history. Balance providers are queried in priority order with failover, in quorum mode disagreements between providers are reported. Fails with 409 while a scheduled or another manual run is in progress on any instance. The accounts of the batch are claimed for the time of the run. A failed account is retried with a backoff and turned off after too many failures in a row. With async the run goes on in the background and 202 is returned with the job id, the progress is polled with GET /cron/jobs/{id}
 // @Tags Cron
 // @Accept json
 // @Produce json
-// @Param X-API-Key header string true "Cron api key"
-// @Success 201 {object} dto.SuccessDto
+// @Param async query bool false "Run in the background. false by default" default(false)
+// @Param X-API-Key header string true "Api key with the cron:run scope"
+// @Success 200 {object} cronModuleDto.UpdateAccountsBalancesResponseDto
+// @Success 202 {object} cronModuleDto.UpdateAccountsBalancesAsyncResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
 // @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 409 {object} errorHelpers.ResponseConflictErrorHTTP{}
+// @Failure 500 {object} errorHelpers.ResponseInternalErrorHTTP{}
 // @Router /cron/account-balance [post]
 func UpdateAccountsBalances(c *gin.Context) {
-	updateAccountsBalances()
-	c.JSON(200, dto.CreateSuccessDto())
+	dto, err := cronModuleDto.CreateUpdateAccountsBalancesRequestDto(c)
+	if err != nil {
+		return
+	}
+	if dto.Async {
+		run, err := runUpdateAccountsBalancesAsync(c, blockchain.Resolver, entities.CronRunTriggerHttp)
+		if err != nil {
+			return
+		}
+		c.JSON(202, cronModuleDto.CreateUpdateAccountsBalancesAsyncResponseDto(run))
+		return
+	}
+	result, err := runUpdateAccountsBalances(c, c.Request.Context(), blockchain.Resolver, entities.CronRunTriggerHttp)
+	if err != nil {
+		return
+	}
+	c.JSON(200, result)
+}
+
+// GetCronJobById Get cron job
+// @Summary Get cron job
+// @Description Get the status of a cron job started with async: the processed and the total count of the accounts, the errors so far and the final status once finished. A job left running by a stopped instance is interrupted
+// @Tags Cron
+// @Accept json
+// @Produce json
+// @Param id path int true "Cron job id" minimum(1)
+// @Param X-API-Key header string true "Api key with the cron:read scope"
+// @Success 200 {object} cronModuleDto.GetCronJobResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /cron/jobs/{id} [get]
+func GetCronJobById(c *gin.Context) {
+	dto, err := cronModuleDto.CreateCronRunIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	run, err := getCronJobById(c, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, cronModuleDto.CreateGetCronJobResponseDto(run))
+}
+
+// GetCronRuns Get cron runs
+// @Summary Get cron runs
+// @Description Get the reports of the cron runs, newest first. A run is partial when some accounts failed and failed when all of them did
+// @Tags Cron
+// @Accept json
+// @Produce json
+// @Param offset query int false "This is paging offset. 0 by default" minimum(0) default(0)
+// @Param count query int false "Max item count in single response. 20 by default" minimum(1) maximum(100) default(20)
+// @Param job query string false "Job of the run" Enums("account-balance")
+// @Param status query string false "Status of the run: running, success, partial, failed, interrupted" Enums("running", "success", "partial", "failed", "interrupted")
+// @Param triggeredBy query string false "Manual run over http or a scheduled one" Enums("http", "scheduler")
+// @Param X-API-Key header string true "Api key with the cron:read scope"
+// @Success 200 {object} cronModuleDto.GetCronRunsResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Router /cron/runs [get]
+func GetCronRuns(c *gin.Context) {
+	dto, err := cronModuleDto.CreateGetCronRunsRequestDto(c)
+	if err != nil {
+		return
+	}
+	runs, total := getCronRuns(dto.Job, dto.Status, dto.TriggeredBy, dto.Offset, dto.Count)
+	c.JSON(200, cronModuleDto.CreateGetCronRunsResponseDto(dto.Offset, dto.Count, total, runs))
+}
+
+// GetCronRunById Get cron run
+// @Summary Get cron run
+// @Description Get the report of a cron run with the error of every failed account and the disagreements of the providers
+// @Tags Cron
+// @Accept json
+// @Produce json
+// @Param id path int true "Cron run id" minimum(1)
+// @Param X-API-Key header string true "Api key with the cron:read scope"
+// @Success 200 {object} cronModuleDto.GetCronRunResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Router /cron/runs/{id} [get]
+func GetCronRunById(c *gin.Context) {
+	dto, err := cronModuleDto.CreateCronRunIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	run, err := getCronRunById(c, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, cronModuleDto.CreateGetCronRunResponseDto(run))
+}
+
+// RefreshAccount Refresh account
+// @Summary Refresh account
+// @Description Update the balance and the transactions of the account right away with the same logic as the cron runs, whether the account is due or not. Returns the old and the new balance, a provider error fails with 502. Fails with 409 while a cron run is refreshing the account
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id" minimum(1)
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Success 200 {object} cronModuleDto.RefreshAccountResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 404 {object} errorHelpers.ResponseNotFoundErrorHTTP{}
+// @Failure 409 {object} errorHelpers.ResponseConflictErrorHTTP{}
+// @Failure 500 {object} errorHelpers.ResponseInternalErrorHTTP{}
+// @Failure 502 {object} errorHelpers.ResponseBadGatewayErrorHTTP{}
+// @Router /account/{id}/refresh [post]
+func RefreshAccount(c *gin.Context) {
+	dto, err := accountModuleDto.CreateAccountIdRequestDto(c)
+	if err != nil {
+		return
+	}
+	refresh, err := refreshAccount(c, blockchain.Resolver, dto.Id)
+	if err != nil {
+		return
+	}
+	c.JSON(200, cronModuleDto.CreateRefreshAccountResponseDto(*refresh))
+}
+
+// RefreshAccounts Refresh accounts
+// @Summary Refresh accounts
+// @Description Refresh up to 100 accounts right away like the refresh of one account. Every account gets its own result in the order of the ids: refreshed, failed with the provider error, busy while a cron run is refreshing it or notFound
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Api key with the account:write scope"
+// @Param request body cronModuleDto.PostRefreshAccountsRequestDto true "Request body"
+// @Success 200 {object} cronModuleDto.PostRefreshAccountsResponseDto
+// @Failure 400 {object} errorHelpers.ResponseBadRequestErrorHTTP{}
+// @Failure 401 {object} errorHelpers.ResponseUnauthorizedErrorHTTP{}
+// @Failure 403 {object} errorHelpers.ResponseForbiddenErrorHTTP{}
+// @Failure 500 {object} errorHelpers.ResponseInternalErrorHTTP{}
+// @Router /account/refresh [post]
+func RefreshAccounts(c *gin.Context) {
+	dto, err := cronModuleDto.CreatePostRefreshAccountsRequestDto(c)
+	if err != nil {
+		return
+	}
+	results, err := refreshAccounts(c, blockchain.Resolver, dto.Ids)
+	if err != nil {
+		return
+	}
+	c.JSON(200, cronModuleDto.CreatePostRefreshAccountsResponseDto(results))
 }
@@ -0,0 +1,246 @@
+package cronModule
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/logger"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	timeUtil "go-gin-test-job/src/utils/time"
+	workerUtil "go-gin-test-job/src/utils/worker"
+	"sync"
+	"time"
+)
+
+// updateAccountsBalancesLock keeps the runs of this instance from overlapping
+var updateAccountsBalancesLock sync.Mutex
+
+// asyncRuns are the cancels of the runs executed in the background by the run id
+var asyncRuns = make(map[int64]context.CancelFunc)
+var asyncRunsLock sync.Mutex
+var asyncRunsGroup sync.WaitGroup
+
+// updateAccountsBalancesJob is a started run holding the locks and the claimed batch
+type updateAccountsBalancesJob struct {
+	ctx         context.Context
+	resolver    *blockchain.BalanceResolver
+	lock        *database.DbLock
+	run         *entities.CronRun
+	startedAt   time.Time
+	accounts    []*entities.Account
+	lockedUntil int64
+}
+
+// runUpdateAccountsBalances fails with a conflict while another run is in progress
+func runUpdateAccountsBalances(c *gin.Context, ctx context.Context, resolver *blockchain.BalanceResolver, triggeredBy string) (*cronModuleDto.UpdateAccountsBalancesResponseDto, error) {
+	job, err := startUpdateAccountsBalances(c, ctx, resolver, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+	return job.execute(), nil
+}
+
+// runUpdateAccountsBalancesAsync returns the stored run once the batch is claimed and goes on in the background
+func runUpdateAccountsBalancesAsync(c *gin.Context, resolver *blockchain.BalanceResolver, triggeredBy string) (*entities.CronRun, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	job, err := startUpdateAccountsBalances(c, ctx, resolver, triggeredBy)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	run := *job.run
+	asyncRunsLock.Lock()
+	asyncRuns[run.Id] = cancel
+	asyncRunsGroup.Add(1)
+	asyncRunsLock.Unlock()
+	go func() {
+		defer asyncRunsGroup.Done()
+		defer func() {
+			asyncRunsLock.Lock()
+			delete(asyncRuns, run.Id)
+			asyncRunsLock.Unlock()
+			cancel()
+		}()
+		job.execute()
+	}()
+	return &run, nil
+}
+
+// WaitAsyncRuns waits for the runs executed in the background to finish
+func WaitAsyncRuns() {
+	asyncRunsGroup.Wait()
+}
+
+// StopAsyncRuns cancels the runs executed in the background and waits for them, a cancelled run is stored as interrupted
+func StopAsyncRuns() {
+	asyncRunsLock.Lock()
+	for _, cancel := range asyncRuns {
+		cancel()
+	}
+	asyncRunsLock.Unlock()
+	asyncRunsGroup.Wait()
+}
+
+// InterruptStaleCronRuns marks the runs left running by a stopped process as interrupted
+func InterruptStaleCronRuns() {
+	lock, err := database.TryLock(context.Background(), entities.CronRunJobAccountBalance)
+	if err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Update accounts balances lock error. %s", err.Error()))
+		return
+	}
+	if lock == nil {
+		return
+	}
+	defer releaseLock(lock)
+	interruptCronRuns()
+}
+
+// interruptCronRuns must be called with the lock held, then no run of the job can be in progress
+func interruptCronRuns() {
+	count, err := database.InterruptCronRuns(entities.CronRunJobAccountBalance, timeUtil.GetUnixTime())
+	if err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Interrupt cron runs of %s error. %s", entities.CronRunJobAccountBalance, err.Error()))
+		return
+	}
+	if count > 0 {
+		logger.Logger.Warn().Msg(fmt.Sprintf("%d cron runs of %s are interrupted", count, entities.CronRunJobAccountBalance))
+	}
+}
+
+func releaseLock(lock *database.DbLock) {
+	if err := lock.Release(); err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Update accounts balances lock release error. %s", err.Error()))
+	}
+}
+
+// startUpdateAccountsBalances takes the locks, claims the batch and stores the run as running
+func startUpdateAccountsBalances(c *gin.Context, ctx context.Context, resolver *blockchain.BalanceResolver, triggeredBy string) (*updateAccountsBalancesJob, error) {
+	if !updateAccountsBalancesLock.TryLock() {
+		return nil, errorHelpers.RespondConflictError(c, "Update accounts balances is already running")
+	}
+	lock, err := database.TryLock(context.Background(), entities.CronRunJobAccountBalance)
+	if err != nil {
+		updateAccountsBalancesLock.Unlock()
+		logger.Logger.Error().Msg(fmt.Sprintf("Update accounts balances lock error. %s", err.Error()))
+		return nil, errorHelpers.RespondInternalError(c, "Update accounts balances lock is not available")
+	}
+	if lock == nil {
+		updateAccountsBalancesLock.Unlock()
+		return nil, errorHelpers.RespondConflictError(c, "Update accounts balances is already running")
+	}
+	job := &updateAccountsBalancesJob{ctx: ctx, resolver: resolver, lock: lock, startedAt: time.Now()}
+	// A run still marked as running was left by a stopped process, no run can be in progress without the lock
+	interruptCronRuns()
+	job.lockedUntil = timeUtil.GetUnixTime() + getAccountsClaimSec(config.AppConfig.CronBatchCount)
+	job.accounts, err = database.ClaimAccountsBatch(config.AppConfig.CronBatchCount, job.lockedUntil)
+	if err != nil {
+		job.release()
+		logger.Logger.Error().Msg(fmt.Sprintf("Claim accounts batch error. %s", err.Error()))
+		return nil, errorHelpers.RespondInternalError(c, "Accounts batch is not available")
+	}
+	job.run = entities.CreateCronRun(entities.CronRunJobAccountBalance, triggeredBy, len(job.accounts), job.startedAt)
+	if err := database.CreateCronRun(nil, job.run); err != nil {
+		job.release()
+		logger.Logger.Error().Msg(fmt.Sprintf("Save cron run of %s error. %s", job.run.Job, err.Error()))
+		return nil, errorHelpers.RespondInternalError(c, "Cron run can not be stored")
+	}
+	return job, nil
+}
+
+// release ends the claim of the batch and frees the locks
+func (j *updateAccountsBalancesJob) release() {
+	releaseAccounts(j.accounts, j.lockedUntil)
+	releaseLock(j.lock)
+	updateAccountsBalancesLock.Unlock()
+}
+
+// execute updates the batch with a bounded pool of workers
+func (j *updateAccountsBalancesJob) execute() *cronModuleDto.UpdateAccountsBalancesResponseDto {
+	defer j.release()
+	results := make([]accountUpdateResult, len(j.accounts))
+	progress := cronModuleDto.NewUpdateAccountsBalancesResponseDto()
+	var progressLock sync.Mutex
+	workerUtil.RunPool(len(j.accounts), config.AppConfig.CronConcurrency, func(index int) {
+		if j.ctx.Err() != nil {
+			return
+		}
+		results[index] = updateAccount(j.ctx, j.resolver, j.accounts[index])
+		progressLock.Lock()
+		defer progressLock.Unlock()
+		addAccountUpdateResult(progress, results[index])
+		j.saveProgress(progress)
+	})
+	// The final report lists the accounts in the order of the batch
+	result := cronModuleDto.NewUpdateAccountsBalancesResponseDto()
+	for _, accountResult := range results {
+		if accountResult.account == nil {
+			continue
+		}
+		addAccountUpdateResult(result, accountResult)
+	}
+	j.finish(result)
+	return result
+}
+
+// saveProgress a progress that can not be stored is only logged, the next account stores it again
+func (j *updateAccountsBalancesJob) saveProgress(progress *cronModuleDto.UpdateAccountsBalancesResponseDto) {
+	setCronRunCounts(j.run, progress)
+	if err := database.UpdateCronRun(nil, j.run, j.run.Progress()); err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Save cron run %d progress error. %s", j.run.Id, err.Error()))
+	}
+}
+
+// finish a run that can not be stored is still returned
+func (j *updateAccountsBalancesJob) finish(result *cronModuleDto.UpdateAccountsBalancesResponseDto) {
+	setCronRunCounts(j.run, result)
+	var updateData map[string]interface{}
+	if j.ctx.Err() != nil {
+		updateData = j.run.Interrupt(j.startedAt, time.Now())
+	} else {
+		updateData = j.run.Finish(j.startedAt, time.Now())
+	}
+	if err := database.UpdateCronRun(nil, j.run, updateData); err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Save cron run %d of %s error. %s", j.run.Id, j.run.Job, err.Error()))
+	}
+	if j.run.Status != entities.CronRunStatusSuccess {
+		logger.Logger.Warn().Msg(fmt.Sprintf("Cron run %d of %s is %s. Processed - %d, failed - %d, transactions failed - %d", j.run.Id, j.run.Job, j.run.Status, j.run.Processed, j.run.Failed, j.run.TransactionsFailed))
+	}
+	result.SetRun(j.run)
+}
+
+func setCronRunCounts(run *entities.CronRun, result *cronModuleDto.UpdateAccountsBalancesResponseDto) {
+	run.Processed = result.Processed
+	run.Updated = result.Updated
+	run.Unchanged = result.Unchanged
+	run.Failed = result.Failed
+	run.Transactions = result.Transactions
+	run.TransactionsFailed = result.TransactionsFailed
+	run.Report = result.GetReport()
+}
+
+func addAccountUpdateResult(result *cronModuleDto.UpdateAccountsBalancesResponseDto, accountResult accountUpdateResult) {
+	account := accountResult.account
+	if accountResult.balanceResult.Disagreed {
+		result.AddDisagreement(account, accountResult.balanceErr == nil, accountResult.balanceResult.Answers)
+	}
+	result.Processed++
+	if accountResult.balanceErr != nil {
+		result.Failed++
+		result.AddError(account, cronModuleDto.AccountUpdateStageBalance, accountResult.balanceErr)
+	} else if accountResult.balanceChanged {
+		result.Updated++
+	} else {
+		result.Unchanged++
+	}
+	if accountResult.transactionsErr != nil {
+		result.TransactionsFailed++
+		result.AddError(account, cronModuleDto.AccountUpdateStageTransactions, accountResult.transactionsErr)
+	} else {
+		result.Transactions += accountResult.transactions
+	}
+}
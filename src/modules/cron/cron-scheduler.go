@@ -0,0 +1,99 @@
+package cronModule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/logger"
+	"go-gin-test-job/src/modules/common/blockchain"
+	scheduleUtil "go-gin-test-job/src/utils/schedule"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"time"
+)
+
+// Scheduler runs the balance update inside the server process
+type Scheduler struct {
+	schedule scheduleUtil.Schedule
+	resolver *blockchain.BalanceResolver
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewScheduler(ctx context.Context, schedule scheduleUtil.Schedule, resolver *blockchain.BalanceResolver) *Scheduler {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Scheduler{
+		schedule: schedule,
+		resolver: resolver,
+		ctx:      ctx,
+		cancel:   cancel,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// NewSchedulerFromConfig returns nil when the scheduler is disabled
+func NewSchedulerFromConfig(ctx context.Context, schedulerConfig config.CronSchedulerConfig, resolver *blockchain.BalanceResolver) (*Scheduler, error) {
+	var schedule scheduleUtil.Schedule
+	var err error
+	switch {
+	case schedulerConfig.Expression != "" && schedulerConfig.IntervalSec != 0:
+		return nil, errors.New("Cron scheduler can not have both an expression and an interval")
+	case schedulerConfig.Expression != "":
+		schedule, err = scheduleUtil.ParseCronExpression(schedulerConfig.Expression)
+	case schedulerConfig.IntervalSec != 0:
+		schedule, err = scheduleUtil.NewIntervalSchedule(timeUtil.DurationSeconds(schedulerConfig.IntervalSec))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewScheduler(ctx, schedule, resolver), nil
+}
+
+func (s *Scheduler) Start() {
+	go s.loop()
+}
+
+// Stop cancels the run in progress and waits for it to end
+func (s *Scheduler) Stop() {
+	s.cancel()
+	close(s.stop)
+	<-s.done
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.done)
+	for {
+		next := s.schedule.Next(time.Now())
+		if next.IsZero() {
+			logger.Logger.Error().Msg("Cron scheduler has no next run time, stopped")
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.run()
+	}
+}
+
+func (s *Scheduler) run() {
+	start := time.Now()
+	result, err := runUpdateAccountsBalances(nil, s.ctx, s.resolver, entities.CronRunTriggerScheduler)
+	if err != nil {
+		logger.Logger.Warn().Msg(fmt.Sprintf("Scheduled update accounts balances skipped. %s", err.Error()))
+		return
+	}
+	logger.Logger.Info().Msg(fmt.Sprintf("Scheduled update accounts balances run %d is %s in %s. Updated - %d, unchanged - %d, failed - %d, transactions - %d", result.RunId, result.Status, time.Since(start), result.Updated, result.Unchanged, result.Failed, result.Transactions))
+}
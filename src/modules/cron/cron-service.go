@@ -1,33 +1,289 @@
 package cronModule
 
 import (
+	"context"
 	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
 	"go-gin-test-job/src/config"
 	"go-gin-test-job/src/database"
 	"go-gin-test-job/src/database/entities"
 	"go-gin-test-job/src/logger"
 	"go-gin-test-job/src/modules/common/blockchain"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	timeUtil "go-gin-test-job/src/utils/time"
+	workerUtil "go-gin-test-job/src/utils/worker"
+	"gorm.io/gorm"
+	"maps"
 )
 
-func updateAccountsBalances() {
-	accounts := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+// accountsClaimMarginSec is added to the claim of the accounts so that it outlives the timeouts of the run
+const accountsClaimMarginSec = 60
+
+func getCronRuns(job string, status string, triggeredBy string, offset int, count int) ([]*entities.CronRun, int64) {
+	filter := database.CronRunFilter{Job: job, Status: status, TriggeredBy: triggeredBy}
+	return database.GetCronRunsAndTotal(filter, offset, count)
+}
+
+func getCronRunById(c *gin.Context, id int64) (*entities.CronRun, error) {
+	run := database.GetCronRunById(id)
+	if run == nil {
+		return nil, errorHelpers.RespondNotFoundError(c, "Cron run not found")
+	}
+	return run, nil
+}
+
+// getCronJobById the job is the run of the job, it is read from the database so any instance can report it
+func getCronJobById(c *gin.Context, id int64) (*entities.CronRun, error) {
+	run := database.GetCronRunById(id)
+	if run == nil {
+		return nil, errorHelpers.RespondNotFoundError(c, "Cron job not found")
+	}
+	return run, nil
+}
+
+// refreshAccount refreshes the account right away, it fails with the provider error when the balance is not available
+func refreshAccount(c *gin.Context, resolver *blockchain.BalanceResolver, id int64) (*cronModuleDto.AccountRefreshDto, error) {
+	results, err := refreshAccounts(c, resolver, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	result := results[0]
+	switch result.Status {
+	case cronModuleDto.RefreshAccountsItemStatusNotFound:
+		return nil, errorHelpers.RespondNotFoundError(c, "Account not found")
+	case cronModuleDto.RefreshAccountsItemStatusBusy:
+		return nil, errorHelpers.RespondConflictError(c, "Account is being refreshed")
+	case cronModuleDto.RefreshAccountsItemStatusFailed:
+		return nil, errorHelpers.RespondBadGatewayError(c, fmt.Sprintf("Account balance is not available. %s", result.Error))
+	}
+	return result.Refresh, nil
+}
+
+// refreshAccounts refreshes the accounts right away, whether they are due or not
+func refreshAccounts(c *gin.Context, resolver *blockchain.BalanceResolver, accountIds []int64) ([]cronModuleDto.RefreshAccountsItemResultDto, error) {
+	existingIds := make([]int64, 0, len(accountIds))
+	for _, account := range database.GetAccountsByIds(accountIds) {
+		if !account.IsDeleted() {
+			existingIds = append(existingIds, account.Id)
+		}
+	}
+	lockedUntil := timeUtil.GetUnixTime() + getAccountsClaimSec(len(existingIds))
+	accounts, err := database.ClaimAccountsByIds(existingIds, lockedUntil)
+	if err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Claim accounts to refresh error. %s", err.Error()))
+		return nil, errorHelpers.RespondInternalError(c, "Account refresh is not available")
+	}
+	defer releaseAccounts(accounts, lockedUntil)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeUtil.DurationSeconds(config.AppConfig.RequestTimeoutSec))
+	defer cancel()
+	updateResults := make([]accountUpdateResult, len(accounts))
+	workerUtil.RunPool(len(accounts), config.AppConfig.CronConcurrency, func(index int) {
+		updateResults[index] = updateAccount(ctx, resolver, accounts[index])
+	})
+	updateResultsById := make(map[int64]accountUpdateResult, len(updateResults))
+	for _, updateResult := range updateResults {
+		updateResultsById[updateResult.account.Id] = updateResult
+	}
+	existingIdSet := make(map[int64]bool, len(existingIds))
+	for _, id := range existingIds {
+		existingIdSet[id] = true
+	}
+	results := make([]cronModuleDto.RefreshAccountsItemResultDto, 0, len(accountIds))
+	for _, id := range accountIds {
+		updateResult, updated := updateResultsById[id]
+		switch {
+		case updated:
+			results = append(results, createRefreshAccountsItemResultDto(updateResult))
+		case existingIdSet[id]:
+			results = append(results, cronModuleDto.RefreshAccountsItemResultDto{AccountId: id, Status: cronModuleDto.RefreshAccountsItemStatusBusy})
+		default:
+			results = append(results, cronModuleDto.RefreshAccountsItemResultDto{AccountId: id, Status: cronModuleDto.RefreshAccountsItemStatusNotFound})
+		}
+	}
+	return results, nil
+}
+
+func createRefreshAccountsItemResultDto(updateResult accountUpdateResult) cronModuleDto.RefreshAccountsItemResultDto {
+	account := updateResult.account
+	if updateResult.balanceErr != nil {
+		return cronModuleDto.RefreshAccountsItemResultDto{
+			AccountId: account.Id,
+			Status:    cronModuleDto.RefreshAccountsItemStatusFailed,
+			Error:     updateResult.balanceErr.Error(),
+		}
+	}
+	refresh := cronModuleDto.AccountRefreshDto{
+		AccountId:             account.Id,
+		Address:               account.Address,
+		OldBalance:            updateResult.oldBalance.String(),
+		NewBalance:            account.Balance.String(),
+		OldUnconfirmedBalance: updateResult.oldUnconfirmed.String(),
+		NewUnconfirmedBalance: account.UnconfirmedBalance.String(),
+		Changed:               updateResult.balanceChanged,
+		Source:                updateResult.balanceResult.Source,
+		Transactions:          updateResult.transactions,
+	}
+	if updateResult.transactionsErr != nil {
+		transactionsError := updateResult.transactionsErr.Error()
+		refresh.TransactionsError = &transactionsError
+		refresh.Transactions = 0
+	}
+	return cronModuleDto.RefreshAccountsItemResultDto{
+		AccountId: account.Id,
+		Status:    cronModuleDto.RefreshAccountsItemStatusRefreshed,
+		Refresh:   &refresh,
+	}
+}
+
+// accountUpdateResult is the outcome of one account. The workers fill their own results and the run merges them in the batch order
+type accountUpdateResult struct {
+	account         *entities.Account
+	oldBalance      decimal.Decimal
+	oldUnconfirmed  decimal.Decimal
+	balanceResult   blockchain.BalanceResult
+	balanceChanged  bool
+	balanceErr      error
+	transactions    int
+	transactionsErr error
+}
+
+// getAccountsClaimSec is the longest time the update of the accounts can take: every round of the workers can reach the timeout of an account
+func getAccountsClaimSec(count int) int64 {
+	concurrency := max(config.AppConfig.CronConcurrency, 1)
+	rounds := (count + concurrency - 1) / concurrency
+	return int64(rounds*config.AppConfig.CronAccountTimeoutSec + accountsClaimMarginSec)
+}
+
+func releaseAccounts(accounts []*entities.Account, lockedUntil int64) {
+	accountIds := make([]int64, 0, len(accounts))
 	for _, account := range accounts {
-		if err := updateAccountBalance(account); err != nil {
-			logger.Logger.Error().Msg(fmt.Sprintf("Update account %d address %s error. %s", account.Id, account.Address, err.Error()))
+		accountIds = append(accountIds, account.Id)
+	}
+	if err := database.ReleaseAccounts(accountIds, lockedUntil); err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Release accounts batch error. %s", err.Error()))
+	}
+}
+
+// updateAccount refreshes the balance and the transactions of the account
+func updateAccount(baseCtx context.Context, resolver *blockchain.BalanceResolver, account *entities.Account) accountUpdateResult {
+	ctx, cancel := context.WithTimeout(baseCtx, timeUtil.DurationSeconds(config.AppConfig.CronAccountTimeoutSec))
+	defer cancel()
+	result := accountUpdateResult{account: account, oldBalance: account.Balance, oldUnconfirmed: account.UnconfirmedBalance}
+	result.balanceResult, result.balanceChanged, result.balanceErr = updateAccountBalance(ctx, resolver, account)
+	if result.balanceErr != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Update account %d address %s error. %s", account.Id, account.Address, result.balanceErr.Error()))
+		if baseCtx.Err() == nil && account.Status != entities.AccountStatusOff {
+			saveAccountRefreshFailure(account, result.balanceErr)
 		}
 	}
+	// The transactions are pulled in the same pass, a failure does not undo the balance update
+	result.transactions, result.transactionsErr = updateAccountTransactions(ctx, resolver, account)
+	if result.transactionsErr != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Update account %d address %s transactions error. %s", account.Id, account.Address, result.transactionsErr.Error()))
+	}
+	return result
 }
 
-func updateAccountBalance(account *entities.Account) error {
+// updateAccountBalance writes the balance and the history of the account and plans its next refresh
+func updateAccountBalance(ctx context.Context, resolver *blockchain.BalanceResolver, account *entities.Account) (blockchain.BalanceResult, bool, error) {
 	logger.Logger.Info().Msg(fmt.Sprintf("Update account %d address %s balance", account.Id, account.Address))
-	balance, err := blockchain.GetAddressBalance(account.Address)
+	balanceResult, err := resolver.GetAddressBalance(ctx, account.Address)
 	if err != nil {
-		return err
+		return balanceResult, false, err
 	}
-	logger.Logger.Info().Msg(fmt.Sprintf("Account %d address %s balance - %s", account.Id, account.Address, account.Balance))
-	updateData := account.UpdateBalance(balance)
-	if err := database.UpdateAccount(nil, account, updateData); err != nil {
-		return err
+	logger.Logger.Info().Msg(fmt.Sprintf("Account %d address %s balance - %s, unconfirmed - %s", account.Id, account.Address, balanceResult.Balance.Confirmed, balanceResult.Balance.Unconfirmed))
+	changed := !account.Balance.Equal(balanceResult.Balance.Confirmed) || !account.UnconfirmedBalance.Equal(balanceResult.Balance.Unconfirmed)
+	// The snapshot is written with the update so the history never misses a change of the confirmed balance
+	return balanceResult, changed, database.DbConn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		oldBalance := account.Balance
+		balance := balanceResult.Balance
+		updateData := account.UpdateBalance(balance.Confirmed, balance.Unconfirmed, balance.Total)
+		maps.Copy(updateData, account.SetRefreshed(account.UpdatedAt, account.UpdatedAt+getAccountRefreshIntervalSec(account)))
+		if err := database.UpdateAccount(tx, account, updateData); err != nil {
+			return err
+		}
+		if oldBalance.Equal(account.Balance) {
+			return nil
+		}
+		history := entities.CreateAccountBalanceHistory(account.Id, oldBalance, account.Balance, balanceResult.Source, account.UpdatedAt)
+		return database.CreateAccountBalanceHistory(tx, history)
+	}, database.DefaultTxOptions)
+}
+
+// saveAccountRefreshFailure plans the retry of the account and turns it off after too many failures
+func saveAccountRefreshFailure(account *entities.Account, refreshErr error) {
+	failures := account.ConsecutiveFailures + 1
+	updateData := account.SetRefreshFailed(refreshErr.Error(), timeUtil.GetUnixTime()+getAccountRetryDelaySec(account, failures))
+	maxFailures := config.AppConfig.CronRefresh.MaxFailures
+	if maxFailures > 0 && failures >= maxFailures {
+		maps.Copy(updateData, account.UpdateStatus(entities.AccountStatusOff))
+		logger.Logger.Warn().Msg(fmt.Sprintf("Account %d address %s is turned off after %d failed refreshes in a row", account.Id, account.Address, failures))
 	}
-	return nil
+	// The run may have reached the timeout of the account, the failure is still stored
+	if err := database.UpdateAccountColumns(nil, account, updateData); err != nil {
+		logger.Logger.Error().Msg(fmt.Sprintf("Save account %d refresh failure error. %s", account.Id, err.Error()))
+	}
+}
+
+// getAccountRefreshIntervalSec is the own interval of the account or the interval of its rank, a higher rank is refreshed more often
+func getAccountRefreshIntervalSec(account *entities.Account) int64 {
+	if account.RefreshIntervalSec > 0 {
+		return int64(account.RefreshIntervalSec)
+	}
+	refreshConfig := config.AppConfig.CronRefresh
+	rank := min(max(int(account.Rank), 0), 100)
+	return int64(refreshConfig.IntervalSec - (refreshConfig.IntervalSec-refreshConfig.MinIntervalSec)*rank/100)
+}
+
+// getAccountRetryDelaySec doubles the refresh interval for every failure in a row
+func getAccountRetryDelaySec(account *entities.Account, failures int) int64 {
+	intervalSec := getAccountRefreshIntervalSec(account)
+	maxDelaySec := max(int64(config.AppConfig.CronRefresh.MaxBackoffSec), intervalSec)
+	delaySec := intervalSec
+	for i := 0; i < failures && delaySec < maxDelaySec; i++ {
+		delaySec *= 2
+	}
+	return min(delaySec, maxDelaySec)
+}
+
+// updateAccountTransactions pulls the transactions from the last stored block
+func updateAccountTransactions(ctx context.Context, resolver *blockchain.BalanceResolver, account *entities.Account) (int, error) {
+	// The transactions are not pulled from the first block again when the last stored one is unknown
+	lastBlockHeight, err := database.GetAccountLastTransactionBlockHeight(account.Id)
+	if err != nil {
+		return 0, err
+	}
+	fromHeight := int64(0)
+	if lastBlockHeight != nil {
+		fromHeight = *lastBlockHeight
+	}
+	count := 0
+	// Every page is saved in its own transaction, the pages saved before an error are kept
+	err = resolver.GetAddressTransactions(ctx, account.Address, fromHeight, func(addressTransactions []blockchain.AddressTransaction) error {
+		transactions := make([]*entities.AccountTransaction, 0, len(addressTransactions))
+		for _, addressTransaction := range addressTransactions {
+			transactions = append(transactions, entities.CreateAccountTransaction(
+				account.Id,
+				addressTransaction.Txid,
+				addressTransaction.BlockHeight,
+				addressTransaction.BlockTime,
+				addressTransaction.AmountIn,
+				addressTransaction.AmountOut,
+				addressTransaction.Fee,
+				addressTransaction.Confirmations,
+			))
+		}
+		// Read committed like the balance update, the workers upsert the rows of different accounts side by side
+		err := database.DbConn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return database.UpsertAccountTransactions(tx, transactions)
+		}, database.DefaultTxOptions)
+		if err != nil {
+			return err
+		}
+		count += len(transactions)
+		return nil
+	})
+	return count, err
 }
@@ -0,0 +1,62 @@
+package cronModuleDto
+
+import (
+	"encoding/json"
+	"go-gin-test-job/src/database/entities"
+)
+
+// CronRunDto startedAt and finishedAt are unix seconds
+type CronRunDto struct {
+	Id                 int64  `json:"id" example:"1"`
+	Job                string `json:"job" example:"account-balance"`
+	TriggeredBy        string `json:"triggeredBy" example:"scheduler"`
+	Status             string `json:"status" example:"partial"`
+	Total              int    `json:"total" example:"5"`
+	Processed          int    `json:"processed" example:"5"`
+	Updated            int    `json:"updated" example:"3"`
+	Unchanged          int    `json:"unchanged" example:"1"`
+	Failed             int    `json:"failed" example:"1"`
+	Transactions       int    `json:"transactions" example:"12"`
+	TransactionsFailed int    `json:"transactionsFailed" example:"0"`
+	StartedAt          int64  `json:"startedAt" example:"1600000000"`
+	FinishedAt         int64  `json:"finishedAt" example:"1600000002"`
+	DurationMs         int64  `json:"durationMs" example:"1520"`
+}
+
+// CronRunReportDto is stored as the report of the run
+type CronRunReportDto struct {
+	Errors        []AccountUpdateErrorDto         `json:"errors"`
+	Disagreements []AccountBalanceDisagreementDto `json:"disagreements"`
+}
+
+func CreateCronRunDto(run *entities.CronRun) CronRunDto {
+	return CronRunDto{
+		Id:                 run.Id,
+		Job:                run.Job,
+		TriggeredBy:        run.TriggeredBy,
+		Status:             run.Status,
+		Total:              run.Total,
+		Processed:          run.Processed,
+		Updated:            run.Updated,
+		Unchanged:          run.Unchanged,
+		Failed:             run.Failed,
+		Transactions:       run.Transactions,
+		TransactionsFailed: run.TransactionsFailed,
+		StartedAt:          run.StartedAt,
+		FinishedAt:         run.FinishedAt,
+		DurationMs:         run.DurationMs,
+	}
+}
+
+// CreateCronRunReportDto reads the stored report, a report that can not be read is returned empty
+func CreateCronRunReportDto(report string) CronRunReportDto {
+	var dto CronRunReportDto
+	_ = json.Unmarshal([]byte(report), &dto)
+	if dto.Errors == nil {
+		dto.Errors = make([]AccountUpdateErrorDto, 0)
+	}
+	if dto.Disagreements == nil {
+		dto.Disagreements = make([]AccountBalanceDisagreementDto, 0)
+	}
+	return dto
+}
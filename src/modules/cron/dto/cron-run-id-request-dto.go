@@ -0,0 +1,55 @@
+package cronModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+)
+
+type CronRunIdRequestDto struct {
+	Id int64 `uri:"id" json:"id" validate:"min=1" example:"1"`
+}
+
+var cronRunIdRequestDtoValidator *validator.Validate
+
+func init() {
+	cronRunIdRequestDtoValidator = validator.New()
+}
+
+func validateCronRunIdRequestDto(dto *CronRunIdRequestDto) error {
+	return cronRunIdRequestDtoValidator.Struct(dto)
+}
+
+// CreateCronRunIdRequestDto is the Gin version of handling the request
+func CreateCronRunIdRequestDto(c *gin.Context) (CronRunIdRequestDto, error) {
+	var dto CronRunIdRequestDto
+	// Parse path params into DTO
+	if err := c.ShouldBindUri(&dto); err != nil {
+		errorMessage := CronRunIdRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Validate the DTO
+	if err := validateCronRunIdRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := CronRunIdRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func CronRunIdRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultFieldErrorMessage("Id")
+}
+
+func CronRunIdRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Id" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
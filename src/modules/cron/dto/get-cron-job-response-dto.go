@@ -0,0 +1,20 @@
+package cronModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+// GetCronJobResponseDto is the run with its progress
+type GetCronJobResponseDto struct {
+	CronRunDto
+	Finished bool `json:"finished" example:"false"`
+	CronRunReportDto
+}
+
+func CreateGetCronJobResponseDto(run *entities.CronRun) GetCronJobResponseDto {
+	return GetCronJobResponseDto{
+		CronRunDto:       CreateCronRunDto(run),
+		Finished:         run.IsFinished(),
+		CronRunReportDto: CreateCronRunReportDto(run.Report),
+	}
+}
@@ -0,0 +1,18 @@
+package cronModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+// GetCronRunResponseDto is the run with the errors and the disagreements of its accounts
+type GetCronRunResponseDto struct {
+	CronRunDto
+	CronRunReportDto
+}
+
+func CreateGetCronRunResponseDto(run *entities.CronRun) GetCronRunResponseDto {
+	return GetCronRunResponseDto{
+		CronRunDto:       CreateCronRunDto(run),
+		CronRunReportDto: CreateCronRunReportDto(run.Report),
+	}
+}
@@ -0,0 +1,91 @@
+package cronModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"go-gin-test-job/src/database/entities"
+	stringUtil "go-gin-test-job/src/utils/string"
+)
+
+const DEFAULT_CRON_RUNS_COUNT = 20
+const DEFAULT_CRON_RUNS_OFFSET = 0
+
+// GetCronRunsRequestDto empty filters are not applied
+type GetCronRunsRequestDto struct {
+	Offset      int    `form:"offset" json:"offset" validate:"min=0" default:"0" example:"5"`
+	Count       int    `form:"count" json:"count" validate:"min=1,max=100" default:"20" example:"20"`
+	Job         string `form:"job" json:"job" validate:"omitempty,oneof=account-balance" example:"account-balance"`
+	Status      string `form:"status" json:"status" validate:"omitempty,oneof=running success partial failed interrupted" example:"failed"`
+	TriggeredBy string `form:"triggeredBy" json:"triggeredBy" validate:"omitempty,oneof=http scheduler" example:"scheduler"`
+}
+
+var getCronRunsRequestDtoValidator *validator.Validate
+
+func init() {
+	getCronRunsRequestDtoValidator = validator.New()
+}
+
+func getCronRunsRequestDtoDefaultValues(dto *GetCronRunsRequestDto) {
+	if dto.Count == 0 {
+		dto.Count = DEFAULT_CRON_RUNS_COUNT
+	}
+}
+
+func validateGetCronRunsRequestDto(dto *GetCronRunsRequestDto) error {
+	return getCronRunsRequestDtoValidator.Struct(dto)
+}
+
+// CreateGetCronRunsRequestDto is the Gin version of handling the request
+func CreateGetCronRunsRequestDto(c *gin.Context) (GetCronRunsRequestDto, error) {
+	var dto GetCronRunsRequestDto
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := GetCronRunsRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Set default values
+	getCronRunsRequestDtoDefaultValues(&dto)
+	// Validate the DTO
+	if err := validateGetCronRunsRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := GetCronRunsRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func GetCronRunsRequestDtoQueryParseErrorMessage(err error) string {
+	var errorMessage string
+	if stringUtil.CaseInsensitiveContains(err.Error(), "\"offset\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".offset") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("offset")
+	} else if stringUtil.CaseInsensitiveContains(err.Error(), "\"count\"") || stringUtil.CaseInsensitiveContains(err.Error(), ".count") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("count")
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
+
+func GetCronRunsRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Count" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Count" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must be less than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Offset" && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("%s must be greater than or equal %s", err.Field(), err.Param())
+	} else if err.Field() == "Job" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s", err.Field(), entities.CronRunJobAccountBalance)
+	} else if err.Field() == "Status" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s,%s,%s,%s,%s", err.Field(), entities.CronRunStatusRunning, entities.CronRunStatusSuccess, entities.CronRunStatusPartial, entities.CronRunStatusFailed, entities.CronRunStatusInterrupted)
+	} else if err.Field() == "TriggeredBy" && err.Tag() == "oneof" {
+		errorMessage = fmt.Sprintf("%s must be one of the next values: %s,%s", err.Field(), entities.CronRunTriggerHttp, entities.CronRunTriggerScheduler)
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
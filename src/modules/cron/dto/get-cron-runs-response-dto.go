@@ -0,0 +1,24 @@
+package cronModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+type GetCronRunsResponseDto struct {
+	Offset int          `json:"offset"`
+	Count  int          `json:"count"`
+	Total  int64        `json:"total"`
+	List   []CronRunDto `json:"list"`
+}
+
+func CreateGetCronRunsResponseDto(offset int, count int, total int64, runs []*entities.CronRun) GetCronRunsResponseDto {
+	var dto GetCronRunsResponseDto
+	dto.Offset = offset
+	dto.Count = count
+	dto.Total = total
+	dto.List = make([]CronRunDto, 0)
+	for _, run := range runs {
+		dto.List = append(dto.List, CreateCronRunDto(run))
+	}
+	return dto
+}
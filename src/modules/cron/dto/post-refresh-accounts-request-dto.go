@@ -0,0 +1,62 @@
+package cronModuleDto
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	"strings"
+)
+
+type PostRefreshAccountsRequestDto struct {
+	Ids []int64 `json:"ids" validate:"required,min=1,max=100,unique,dive,min=1" example:"1,2"`
+}
+
+var postRefreshAccountsRequestDtoValidator *validator.Validate
+
+func init() {
+	postRefreshAccountsRequestDtoValidator = validator.New()
+}
+
+func validatePostRefreshAccountsRequestDto(dto *PostRefreshAccountsRequestDto) error {
+	return postRefreshAccountsRequestDtoValidator.Struct(dto)
+}
+
+// CreatePostRefreshAccountsRequestDto is the Gin version for handling the request
+func CreatePostRefreshAccountsRequestDto(c *gin.Context) (PostRefreshAccountsRequestDto, error) {
+	var dto PostRefreshAccountsRequestDto
+	// Parse body params into DTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errorMessage := PostRefreshAccountsRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	// Validate the DTO
+	if err := validatePostRefreshAccountsRequestDto(&dto); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessage := PostRefreshAccountsRequestDtoValidateErrorMessage(err)
+			return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+		}
+	}
+	return dto, nil
+}
+
+func PostRefreshAccountsRequestDtoQueryParseErrorMessage(err error) string {
+	return errorMessages.DefaultQueryParseErrorMessage()
+}
+
+func PostRefreshAccountsRequestDtoValidateErrorMessage(err validator.FieldError) string {
+	var errorMessage string
+	if err.Field() == "Ids" && (err.Tag() == "required" || err.Tag() == "min") {
+		errorMessage = fmt.Sprintf("%s must contain at least 1 item", err.Field())
+	} else if err.Field() == "Ids" && err.Tag() == "max" {
+		errorMessage = fmt.Sprintf("%s must contain at most %s items", err.Field(), err.Param())
+	} else if err.Field() == "Ids" && err.Tag() == "unique" {
+		errorMessage = fmt.Sprintf("%s must not contain duplicates", err.Field())
+	} else if strings.HasPrefix(err.Field(), "Ids[") && err.Tag() == "min" {
+		errorMessage = fmt.Sprintf("Every id must be greater than or equal %s", err.Param())
+	} else {
+		errorMessage = errorMessages.DefaultFieldErrorMessage(err.Field())
+	}
+	return errorMessage
+}
@@ -0,0 +1,39 @@
+package cronModuleDto
+
+type RefreshAccountsItemStatus string
+
+const (
+	RefreshAccountsItemStatusRefreshed RefreshAccountsItemStatus = "refreshed"
+	// RefreshAccountsItemStatusFailed the providers did not give the balance, the error is the provider error
+	RefreshAccountsItemStatusFailed RefreshAccountsItemStatus = "failed"
+	// RefreshAccountsItemStatusBusy the account is being refreshed by a cron run or another request
+	RefreshAccountsItemStatusBusy     RefreshAccountsItemStatus = "busy"
+	RefreshAccountsItemStatusNotFound RefreshAccountsItemStatus = "notFound"
+)
+
+type RefreshAccountsItemResultDto struct {
+	AccountId int64                     `json:"accountId" example:"1"`
+	Status    RefreshAccountsItemStatus `json:"status" enums:"refreshed,failed,busy,notFound" example:"refreshed"`
+	Error     string                    `json:"error,omitempty" example:"Balance request failed with status 500"`
+	Refresh   *AccountRefreshDto        `json:"refresh,omitempty"`
+}
+
+// PostRefreshAccountsResponseDto results are in the order of the requested ids
+type PostRefreshAccountsResponseDto struct {
+	Refreshed int                            `json:"refreshed" example:"1"`
+	Failed    int                            `json:"failed" example:"0"`
+	Results   []RefreshAccountsItemResultDto `json:"results"`
+}
+
+func CreatePostRefreshAccountsResponseDto(results []RefreshAccountsItemResultDto) PostRefreshAccountsResponseDto {
+	var dto PostRefreshAccountsResponseDto
+	dto.Results = results
+	for _, result := range results {
+		if result.Status == RefreshAccountsItemStatusRefreshed {
+			dto.Refreshed++
+		} else {
+			dto.Failed++
+		}
+	}
+	return dto
+}
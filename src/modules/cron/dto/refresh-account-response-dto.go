@@ -0,0 +1,27 @@
+package cronModuleDto
+
+// AccountRefreshDto old and new are the balances before and after the refresh
+type AccountRefreshDto struct {
+	AccountId             int64   `json:"accountId" example:"1"`
+	Address               string  `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	OldBalance            string  `json:"oldBalance" example:"12.1234"`
+	NewBalance            string  `json:"newBalance" example:"12.5"`
+	OldUnconfirmedBalance string  `json:"oldUnconfirmedBalance" example:"0.5"`
+	NewUnconfirmedBalance string  `json:"newUnconfirmedBalance" example:"0"`
+	Changed               bool    `json:"changed" example:"true"`
+	Source                string  `json:"source" example:"bitcore"`
+	Transactions          int     `json:"transactions" example:"2"`
+	TransactionsError     *string `json:"transactionsError" example:"context deadline exceeded"`
+}
+
+type RefreshAccountResponseDto struct {
+	Success bool `json:"success" default:"true"`
+	AccountRefreshDto
+}
+
+func CreateRefreshAccountResponseDto(refresh AccountRefreshDto) RefreshAccountResponseDto {
+	return RefreshAccountResponseDto{
+		Success:           true,
+		AccountRefreshDto: refresh,
+	}
+}
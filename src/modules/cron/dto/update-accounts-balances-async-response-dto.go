@@ -0,0 +1,24 @@
+package cronModuleDto
+
+import (
+	"go-gin-test-job/src/database/entities"
+)
+
+// UpdateAccountsBalancesAsyncResponseDto jobId is the id of the stored run, its progress is polled with GET /cron/jobs/{id}
+type UpdateAccountsBalancesAsyncResponseDto struct {
+	Success   bool   `json:"success" default:"true"`
+	JobId     int64  `json:"jobId" example:"1"`
+	Status    string `json:"status" example:"running"`
+	Total     int    `json:"total" example:"5"`
+	StartedAt int64  `json:"startedAt" example:"1600000000"`
+}
+
+func CreateUpdateAccountsBalancesAsyncResponseDto(run *entities.CronRun) UpdateAccountsBalancesAsyncResponseDto {
+	return UpdateAccountsBalancesAsyncResponseDto{
+		Success:   true,
+		JobId:     run.Id,
+		Status:    run.Status,
+		Total:     run.Total,
+		StartedAt: run.StartedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package cronModuleDto
+
+import (
+	"github.com/gin-gonic/gin"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	errorMessages "go-gin-test-job/src/common/error-messages"
+	stringUtil "go-gin-test-job/src/utils/string"
+)
+
+// UpdateAccountsBalancesRequestDto async runs the update in the background and returns the job right away
+type UpdateAccountsBalancesRequestDto struct {
+	Async bool `form:"async" json:"async" default:"false" example:"true"`
+}
+
+// CreateUpdateAccountsBalancesRequestDto is the Gin version of handling the request
+func CreateUpdateAccountsBalancesRequestDto(c *gin.Context) (UpdateAccountsBalancesRequestDto, error) {
+	var dto UpdateAccountsBalancesRequestDto
+	// Parse query params into DTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		errorMessage := UpdateAccountsBalancesRequestDtoQueryParseErrorMessage(err)
+		return dto, errorHelpers.RespondBadRequestError(c, errorMessage)
+	}
+	return dto, nil
+}
+
+func UpdateAccountsBalancesRequestDtoQueryParseErrorMessage(err error) string {
+	var errorMessage string
+	if stringUtil.CaseInsensitiveContains(err.Error(), "ParseBool") {
+		errorMessage = errorMessages.DefaultFieldErrorMessage("async")
+	} else {
+		errorMessage = errorMessages.DefaultQueryParseErrorMessage()
+	}
+	return errorMessage
+}
@@ -0,0 +1,109 @@
+package cronModuleDto
+
+import (
+	"encoding/json"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+)
+
+// ProviderBalanceDto balances are null when the provider failed
+type ProviderBalanceDto struct {
+	Provider           string  `json:"provider" example:"bitcore"`
+	Balance            *string `json:"balance" example:"12.1234"`
+	UnconfirmedBalance *string `json:"unconfirmed_balance" example:"0.5"`
+	Error              *string `json:"error" example:"Balance request failed with status 500"`
+}
+
+// AccountBalanceDisagreementDto written tells whether the quorum was still reached and the balance was stored
+type AccountBalanceDisagreementDto struct {
+	AccountId int64                `json:"accountId" example:"1"`
+	Address   string               `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Written   bool                 `json:"written" example:"false"`
+	Balances  []ProviderBalanceDto `json:"balances"`
+}
+
+const (
+	AccountUpdateStageBalance      = "balance"
+	AccountUpdateStageTransactions = "transactions"
+)
+
+// AccountUpdateErrorDto stage is balance or transactions, an account can fail both
+type AccountUpdateErrorDto struct {
+	AccountId int64  `json:"accountId" example:"1"`
+	Address   string `json:"address" example:"1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"`
+	Stage     string `json:"stage" example:"balance"`
+	Error     string `json:"error" example:"context deadline exceeded"`
+}
+
+// UpdateAccountsBalancesResponseDto is the report of the run
+type UpdateAccountsBalancesResponseDto struct {
+	Success            bool                            `json:"success" default:"true"`
+	RunId              int64                           `json:"runId" example:"1"`
+	Status             string                          `json:"status" example:"partial"`
+	Processed          int                             `json:"processed" example:"5"`
+	Updated            int                             `json:"updated" example:"3"`
+	Unchanged          int                             `json:"unchanged" example:"1"`
+	Failed             int                             `json:"failed" example:"1"`
+	Transactions       int                             `json:"transactions" example:"12"`
+	TransactionsFailed int                             `json:"transactionsFailed" example:"0"`
+	StartedAt          int64                           `json:"startedAt" example:"1600000000"`
+	FinishedAt         int64                           `json:"finishedAt" example:"1600000002"`
+	DurationMs         int64                           `json:"durationMs" example:"1520"`
+	Disagreements      []AccountBalanceDisagreementDto `json:"disagreements"`
+	Errors             []AccountUpdateErrorDto         `json:"errors"`
+}
+
+func NewUpdateAccountsBalancesResponseDto() *UpdateAccountsBalancesResponseDto {
+	return &UpdateAccountsBalancesResponseDto{
+		Success:       true,
+		Disagreements: make([]AccountBalanceDisagreementDto, 0),
+		Errors:        make([]AccountUpdateErrorDto, 0),
+	}
+}
+
+func (dto *UpdateAccountsBalancesResponseDto) AddDisagreement(account *entities.Account, written bool, answers []blockchain.ProviderBalance) {
+	balances := make([]ProviderBalanceDto, 0, len(answers))
+	for _, answer := range answers {
+		balanceDto := ProviderBalanceDto{Provider: answer.Provider}
+		if answer.Err != nil {
+			errorMessage := answer.Err.Error()
+			balanceDto.Error = &errorMessage
+		} else {
+			balance := answer.Balance.Confirmed.String()
+			unconfirmedBalance := answer.Balance.Unconfirmed.String()
+			balanceDto.Balance = &balance
+			balanceDto.UnconfirmedBalance = &unconfirmedBalance
+		}
+		balances = append(balances, balanceDto)
+	}
+	dto.Disagreements = append(dto.Disagreements, AccountBalanceDisagreementDto{
+		AccountId: account.Id,
+		Address:   account.Address,
+		Written:   written,
+		Balances:  balances,
+	})
+}
+
+// SetRun takes the id, the status and the times of the stored run
+func (dto *UpdateAccountsBalancesResponseDto) SetRun(run *entities.CronRun) {
+	dto.RunId = run.Id
+	dto.Status = run.Status
+	dto.StartedAt = run.StartedAt
+	dto.FinishedAt = run.FinishedAt
+	dto.DurationMs = run.DurationMs
+}
+
+// GetReport is the json stored as the report of the run
+func (dto *UpdateAccountsBalancesResponseDto) GetReport() string {
+	report, _ := json.Marshal(CronRunReportDto{Errors: dto.Errors, Disagreements: dto.Disagreements})
+	return string(report)
+}
+
+func (dto *UpdateAccountsBalancesResponseDto) AddError(account *entities.Account, stage string, err error) {
+	dto.Errors = append(dto.Errors, AccountUpdateErrorDto{
+		AccountId: account.Id,
+		Address:   account.Address,
+		Stage:     stage,
+		Error:     err.Error(),
+	})
+}
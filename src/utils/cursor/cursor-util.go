@@ -0,0 +1,32 @@
+package cursorUtil
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// cursor is the payload of an opaque pagination cursor
+type cursor struct {
+	Signature string   `json:"s"`
+	Values    []string `json:"v"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func Encode(signature string, values []string) string {
+	data, _ := json.Marshal(cursor{Signature: signature, Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(value string) (string, []string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return "", nil, ErrInvalidCursor
+	}
+	var result cursor
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", nil, ErrInvalidCursor
+	}
+	return result.Signature, result.Values, nil
+}
@@ -12,8 +12,15 @@ var AvailableSortOrderList = map[string]bool{
 	"DESC": true,
 }
 
-func GetOrderByParamsSecure(c *gin.Context, data, separator string, availableSortFieldList []string) (map[string]string, error) {
-	orderByResult := make(map[string]string)
+// OrderParam is a single sort field with its direction. The position in the list is the sort priority
+type OrderParam struct {
+	Field     string
+	Direction string
+}
+
+func GetOrderByParamsSecure(c *gin.Context, data, separator string, availableSortFieldList []string) ([]OrderParam, error) {
+	orderByResult := make([]OrderParam, 0)
+	orderByFields := make(map[string]bool)
 	availableSortFields := make(map[string]bool)
 	// Convert availableSortFieldList to a map for faster lookup
 	for _, field := range availableSortFieldList {
@@ -48,10 +55,21 @@ func GetOrderByParamsSecure(c *gin.Context, data, separator string, availableSor
 			return nil, errorHelpers.RespondBadRequestError(c, fmt.Sprintf("invalid order direction: %s", direction))
 		}
 		// Avoid duplicate order fields
-		if _, exists := orderByResult[order]; !exists {
-			orderByResult[order] = direction
+		if !orderByFields[order] {
+			orderByFields[order] = true
+			orderByResult = append(orderByResult, OrderParam{Field: order, Direction: direction})
 		}
 	}
 
 	return orderByResult, nil
 }
+
+// HasOrderField reports whether the field is already in the order params
+func HasOrderField(orderParams []OrderParam, field string) bool {
+	for _, orderParam := range orderParams {
+		if orderParam.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package scheduleUtil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears limits the search of the next time, an expression like `0 0 30 2 *` never matches
+const cronSearchYears = 5
+
+// Schedule returns the next run time strictly after the given time, the zero time means there is no next run
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// NewIntervalSchedule runs every interval counting from the end of the previous run
+func NewIntervalSchedule(interval time.Duration) (Schedule, error) {
+	if interval <= 0 {
+		return nil, errors.New("Interval must be positive")
+	}
+	return &intervalSchedule{interval: interval}, nil
+}
+
+func (s *intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// cronSchedule keeps a bit per allowed value of every field
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// With both day fields restricted a day matches either of them, as in crontab. A field starting with `*` is not restricted
+	dayOfMonthAny bool
+	dayOfWeekAny  bool
+}
+
+// ParseCronExpression parses the 5 fields of crontab: minute, hour, day of month, month and day of week
+func ParseCronExpression(expression string) (Schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("Cron expression must have %d fields, got %d", len(cronFields), len(fields))
+	}
+	bits := make([]uint64, len(fields))
+	for index, field := range fields {
+		value, err := parseCronField(field, cronFields[index])
+		if err != nil {
+			return nil, err
+		}
+		bits[index] = value
+	}
+	// Sunday 7 is the same day as Sunday 0
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = (bits[4] | 1) &^ (1 << 7)
+	}
+	schedule := &cronSchedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dayOfMonth:    bits[2],
+		month:         bits[3],
+		dayOfWeek:     bits[4],
+		dayOfMonthAny: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekAny:  strings.HasPrefix(fields[4], "*"),
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("Cron expression %s never matches", expression)
+	}
+	return schedule, nil
+}
+
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if slash := strings.Index(item, "/"); slash >= 0 {
+			rangePart = item[:slash]
+			value, err := strconv.Atoi(item[slash+1:])
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("Cron %s step %s is invalid", spec.name, item)
+			}
+			step = value
+		}
+		from, to := spec.min, spec.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			value, err := parseCronValue(bounds[0], spec)
+			if err != nil {
+				return 0, err
+			}
+			from, to = value, value
+			if len(bounds) == 2 {
+				if to, err = parseCronValue(bounds[1], spec); err != nil {
+					return 0, err
+				}
+				if to < from {
+					return 0, fmt.Errorf("Cron %s range %s is invalid", spec.name, rangePart)
+				}
+			} else if step > 1 {
+				// `a/n` runs from a to the end of the field
+				to = spec.max
+			}
+		}
+		for value := from; value <= to; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, spec cronField) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < spec.min || number > spec.max {
+		return 0, fmt.Errorf("Cron %s value %s must be from %d to %d", spec.name, value, spec.min, spec.max)
+	}
+	return number, nil
+}
+
+// Next moves forward by the largest unit that does not match, so a year is searched in a few hundred steps
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	location := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
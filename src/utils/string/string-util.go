@@ -4,6 +4,13 @@ import (
 	"strings"
 )
 
+var likeReplacer = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
 func CaseInsensitiveContains(str string, substr string) bool {
 	return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
 }
+
+// EscapeLike escapes the wildcards of a LIKE pattern so that the string is matched literally
+func EscapeLike(str string) string {
+	return likeReplacer.Replace(str)
+}
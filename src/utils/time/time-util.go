@@ -19,3 +19,7 @@ func MillisFromSec(time int64) int64 {
 func DurationSeconds(value int) time.Duration {
 	return time.Duration(value) * time.Second
 }
+
+func DurationMillis(value int) time.Duration {
+	return time.Duration(value) * time.Millisecond
+}
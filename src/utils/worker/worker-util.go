@@ -0,0 +1,31 @@
+package workerUtil
+
+import (
+	"sync"
+)
+
+// RunPool calls work for every index from 0 to count-1 with at most concurrency calls at a time
+func RunPool(count int, concurrency int, work func(index int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > count {
+		concurrency = count
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				work(index)
+			}
+		}()
+	}
+	for index := 0; index < count; index++ {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -5,9 +5,11 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go-gin-test-job/src/database/entities"
 	logger "go-gin-test-job/src/logger"
 	middleware "go-gin-test-job/src/middlewares"
 	accountModule "go-gin-test-job/src/modules/account"
+	apiKeyModule "go-gin-test-job/src/modules/api-key"
 	cronModule "go-gin-test-job/src/modules/cron"
 )
 
@@ -28,12 +30,39 @@ func New() *gin.Engine {
 
 	// Account routes
 	accountMethods := app.Group("/account")
-	accountMethods.GET("", middleware.AdminApiKeyGuard(), accountModule.GetAccounts)
-	accountMethods.POST("", middleware.AdminApiKeyGuard(), accountModule.CreateAccount)
+	accountMethods.GET("", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccounts)
+	accountMethods.POST("", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.CreateAccount)
+	accountMethods.POST("/bulk", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.BulkCreateAccounts)
+	accountMethods.GET("/stats", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountStats)
+	accountMethods.GET("/utxos", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountsUtxos)
+	accountMethods.GET("/export", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.ExportAccounts)
+	accountMethods.POST("/import", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.ImportAccounts)
+	accountMethods.POST("/refresh", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), cronModule.RefreshAccounts)
+	accountMethods.GET("/by-address/:address", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountByAddress)
+	accountMethods.GET("/:id", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountById)
+	accountMethods.PATCH("/:id", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.PatchAccount)
+	accountMethods.DELETE("/:id", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.DeleteAccount)
+	accountMethods.POST("/:id/restore", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.RestoreAccount)
+	accountMethods.GET("/:id/tags", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountTags)
+	accountMethods.POST("/:id/tags", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.AddAccountTags)
+	accountMethods.DELETE("/:id/tags/:tag", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), accountModule.RemoveAccountTag)
+	accountMethods.GET("/:id/balance-history", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountBalanceHistory)
+	accountMethods.GET("/:id/transactions", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountTransactions)
+	accountMethods.GET("/:id/utxos", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountRead), accountModule.GetAccountUtxos)
+	accountMethods.POST("/:id/refresh", middleware.ApiKeyGuard(entities.ApiKeyScopeAccountWrite), cronModule.RefreshAccount)
 
 	// Cron routes
 	cronMethods := app.Group("/cron")
-	cronMethods.POST("/account-balance", middleware.CronApiKeyGuard(), cronModule.UpdateAccountsBalances)
+	cronMethods.POST("/account-balance", middleware.ApiKeyGuard(entities.ApiKeyScopeCronRun), cronModule.UpdateAccountsBalances)
+	cronMethods.GET("/runs", middleware.ApiKeyGuard(entities.ApiKeyScopeCronRead), cronModule.GetCronRuns)
+	cronMethods.GET("/runs/:id", middleware.ApiKeyGuard(entities.ApiKeyScopeCronRead), cronModule.GetCronRunById)
+	cronMethods.GET("/jobs/:id", middleware.ApiKeyGuard(entities.ApiKeyScopeCronRead), cronModule.GetCronJobById)
+
+	// Api key routes
+	apiKeyMethods := app.Group("/api-key")
+	apiKeyMethods.GET("", middleware.ApiKeyGuard(entities.ApiKeyScopeApiKeyManage), apiKeyModule.GetApiKeys)
+	apiKeyMethods.POST("", middleware.ApiKeyGuard(entities.ApiKeyScopeApiKeyManage), apiKeyModule.CreateApiKey)
+	apiKeyMethods.DELETE("/:id", middleware.ApiKeyGuard(entities.ApiKeyScopeApiKeyManage), apiKeyModule.RevokeApiKey)
 
 	return app
 }
@@ -16,50 +16,55 @@ var ACCOUNTS struct {
 func FillAccountList() []entities.Account {
 	memo1 := "This is the main account"
 	memo2 := "Secondary account for transactions"
-	
+
 	ACCOUNTS.ACCOUNT_1 = entities.Account{
-		Id:        1,
-		Address:   "3JTCWLKubxuuXXnmQPxx43nP2LJAcPSL1W",
-		Name:      "Main Account",
-		Rank:      90,
-		Memo:      &memo1,
-		Balance:   decimal.RequireFromString("0.96224397"),
-		Status:    entities.AccountStatusOn,
-		CreatedAt: timeUtil.GetUnixTime(),
-		UpdatedAt: timeUtil.GetUnixTime(),
+		Id:                 1,
+		Address:            "3JTCWLKubxuuXXnmQPxx43nP2LJAcPSL1W",
+		Name:               "Main Account",
+		Rank:               90,
+		Memo:               &memo1,
+		Balance:            decimal.RequireFromString("0.96224397"),
+		UnconfirmedBalance: decimal.RequireFromString("0.0015"),
+		TotalBalance:       decimal.RequireFromString("0.96374397"),
+		Status:             entities.AccountStatusOn,
+		CreatedAt:          timeUtil.GetUnixTime(),
+		UpdatedAt:          timeUtil.GetUnixTime(),
 	}
 	ACCOUNTS.ACCOUNT_2 = entities.Account{
-		Id:        2,
-		Address:   "38JeTiYSS2Y4kSxNBNH6kmH5kjm8sodDvU",
-		Name:      "Secondary Account",
-		Rank:      75,
-		Memo:      &memo2,
-		Balance:   decimal.RequireFromString("0.00056665"),
-		Status:    entities.AccountStatusOn,
-		CreatedAt: timeUtil.GetUnixTime(),
-		UpdatedAt: timeUtil.GetUnixTime(),
+		Id:           2,
+		Address:      "38JeTiYSS2Y4kSxNBNH6kmH5kjm8sodDvU",
+		Name:         "Secondary Account",
+		Rank:         75,
+		Memo:         &memo2,
+		Balance:      decimal.RequireFromString("0.00056665"),
+		TotalBalance: decimal.RequireFromString("0.00056665"),
+		Status:       entities.AccountStatusOn,
+		CreatedAt:    timeUtil.GetUnixTime(),
+		UpdatedAt:    timeUtil.GetUnixTime(),
 	}
 	ACCOUNTS.ACCOUNT_3 = entities.Account{
-		Id:        3,
-		Address:   "34bMmbjiiK5WfV2ZtgZGxLVYycJGNPEqjE",
-		Name:      "Reserve Account",
-		Rank:      50,
-		Memo:      nil,
-		Balance:   decimal.NewFromInt(0),
-		Status:    entities.AccountStatusOff,
-		CreatedAt: timeUtil.GetUnixTime(),
-		UpdatedAt: timeUtil.GetUnixTime(),
+		Id:           3,
+		Address:      "34bMmbjiiK5WfV2ZtgZGxLVYycJGNPEqjE",
+		Name:         "Reserve Account",
+		Rank:         50,
+		Memo:         nil,
+		Balance:      decimal.NewFromInt(0),
+		TotalBalance: decimal.NewFromInt(0),
+		Status:       entities.AccountStatusOff,
+		CreatedAt:    timeUtil.GetUnixTime(),
+		UpdatedAt:    timeUtil.GetUnixTime(),
 	}
 	ACCOUNTS.ACCOUNT_4 = entities.Account{
-		Id:        4,
-		Address:   "1CmSPVJifmK3HXqy2tYgbTSb4eExK4wqYT",
-		Name:      "Backup Account",
-		Rank:      25,
-		Memo:      nil,
-		Balance:   decimal.RequireFromString("0.07134313"),
-		Status:    entities.AccountStatusOff,
-		CreatedAt: timeUtil.GetUnixTime(),
-		UpdatedAt: timeUtil.GetUnixTime(),
+		Id:           4,
+		Address:      "1CmSPVJifmK3HXqy2tYgbTSb4eExK4wqYT",
+		Name:         "Backup Account",
+		Rank:         25,
+		Memo:         nil,
+		Balance:      decimal.RequireFromString("0.07134313"),
+		TotalBalance: decimal.RequireFromString("0.07134313"),
+		Status:       entities.AccountStatusOff,
+		CreatedAt:    timeUtil.GetUnixTime(),
+		UpdatedAt:    timeUtil.GetUnixTime(),
 	}
 	return []entities.Account{
 		ACCOUNTS.ACCOUNT_1,
@@ -86,7 +91,7 @@ func SeedAccount(accounts []entities.Account) {
 		ACCOUNT_3 entities.Account
 		ACCOUNT_4 entities.Account
 	}{}
-	
+
 	// Add any new accounts
 	for i, account := range accounts {
 		switch i {
@@ -8,6 +8,7 @@ import (
 	"go-gin-test-job/src/database/entities"
 	"go-gin-test-job/src/logger"
 	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/src/modules/common/blockchain"
 	testDatabase "go-gin-test-job/test/database"
 	testRoutes "go-gin-test-job/test/routes"
 	"go-gin-test-job/test/seeds"
@@ -53,6 +54,9 @@ func InitApp() *gin.Engine {
 	createTestData()
 	// Set DbConn for app
 	appDatabase.DbConn = testDatabase.DbConn
+	if err := blockchain.InitBalanceResolver(); err != nil {
+		logger.Logger.Fatal().Msg("Init balance resolver error. Error - " + err.Error())
+	}
 	TestAppConfig = &TestServerConfig{
 		Host: "localhost",
 		Port: 8080,
@@ -177,7 +181,24 @@ func CompareAccount(t *testing.T, account *entities.Account, accountDto accountM
 		assert.Equal(t, *account.Memo, *accountDto.Memo)
 	}
 	assert.Equal(t, account.Balance.String(), accountDto.Balance)
+	assert.Equal(t, account.UnconfirmedBalance.String(), accountDto.UnconfirmedBalance)
+	assert.Equal(t, account.TotalBalance.String(), accountDto.TotalBalance)
 	assert.Equal(t, string(account.Status), accountDto.Status)
 	assert.Equal(t, account.CreatedAt, accountDto.CreatedAt)
 	assert.Equal(t, account.UpdatedAt, accountDto.UpdatedAt)
+	if account.IsDeleted() {
+		assert.Equal(t, account.DeletedAt, *accountDto.DeletedAt)
+	} else {
+		assert.Nil(t, accountDto.DeletedAt)
+	}
+	assert.Equal(t, account.NextRefreshAt, accountDto.NextRefreshAt)
+	if account.LastRefreshedAt == 0 {
+		assert.Nil(t, accountDto.LastRefreshedAt)
+	} else {
+		assert.Equal(t, account.LastRefreshedAt, *accountDto.LastRefreshedAt)
+	}
+	assert.Equal(t, account.RefreshIntervalSec, accountDto.RefreshIntervalSec)
+	assert.Equal(t, account.ConsecutiveFailures, accountDto.ConsecutiveFailures)
+	assert.Equal(t, account.LastError, accountDto.LastError)
+	assert.Equal(t, accountModuleDto.CreateAccountTagNameList(account.Tags), accountDto.Tags)
 }
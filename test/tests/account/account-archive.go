@@ -0,0 +1,203 @@
+package accountTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const archiveTestAddress = "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"
+
+func TestDeleteAccountRoute_FailNotFound(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("DELETE", "/account/999999", nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestDeleteAndRestoreAccountRoute_Success(t *testing.T) {
+	// Clean up any existing test accounts
+	database.DbConn.Where("address = ?", archiveTestAddress).Delete(&entities.Account{})
+
+	account := entities.Account{
+		Address: archiveTestAddress,
+		Name:    "Archived Account",
+		Rank:    50,
+		Status:  entities.AccountStatusOn,
+	}
+	result := database.DbConn.Create(&account)
+	assert.Nil(t, result.Error)
+
+	// Archive the account
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("DELETE", fmt.Sprintf("/account/%d", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var deleteResponseDto accountModuleDto.AccountDto
+	err := json.NewDecoder(response.Body).Decode(&deleteResponseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, account.Id, deleteResponseDto.Id)
+	assert.NotNil(t, deleteResponseDto.DeletedAt)
+
+	// Archived account is hidden from the list unless includeDeleted is sent
+	assert.False(t, isAccountInList(t, account.Id, false))
+	assert.True(t, isAccountInList(t, account.Id, true))
+
+	// Archived account is not returned by address and the address can be registered again
+	assert.Nil(t, database.GetAccountByAddress(archiveTestAddress))
+	body, _ := json.Marshal(map[string]interface{}{
+		"address": archiveTestAddress,
+		"name":    "New Account",
+		"rank":    60,
+		"status":  entities.AccountStatusOn,
+	})
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("POST", "/account", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var createResponseDto accountModuleDto.AccountDto
+	err = json.NewDecoder(response.Body).Decode(&createResponseDto)
+	assert.Nil(t, err)
+	assert.NotEqual(t, account.Id, createResponseDto.Id)
+
+	// Restore fails while the address is used by another account
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("POST", fmt.Sprintf("/account/%d/restore", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	var conflictResponseDto errorHelpers.ResponseConflictErrorHTTP
+	err = json.NewDecoder(response.Body).Decode(&conflictResponseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, "Address already exists", conflictResponseDto.Message)
+
+	// Restore succeeds once the address is free again
+	database.DbConn.Where("id = ?", createResponseDto.Id).Delete(&entities.Account{})
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("POST", fmt.Sprintf("/account/%d/restore", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var restoreResponseDto accountModuleDto.AccountDto
+	err = json.NewDecoder(response.Body).Decode(&restoreResponseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, account.Id, restoreResponseDto.Id)
+	assert.Nil(t, restoreResponseDto.DeletedAt)
+	assert.True(t, isAccountInList(t, account.Id, false))
+
+	// Restore of an active account is a conflict
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("POST", fmt.Sprintf("/account/%d/restore", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	// Clean up
+	database.DbConn.Where("address = ?", archiveTestAddress).Delete(&entities.Account{})
+}
+
+func isAccountInList(t *testing.T, accountId int64, includeDeleted bool) bool {
+	query := url.Values{}
+	query.Add("includeDeleted", fmt.Sprintf("%t", includeDeleted))
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account"),
+		RawQuery: query.Encode(),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto accountModuleDto.GetAccountResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	for _, accountDto := range responseDto.List {
+		if accountDto.Id == accountId {
+			return true
+		}
+	}
+	return false
+}
+
+func TestArchivedAccountRoute_FailNotFound(t *testing.T) {
+	database.DbConn.Where("address = ?", archiveTestAddress).Delete(&entities.Account{})
+	account, err := database.CreateAccount(nil, entities.CreateAccount(archiveTestAddress, "Archived Account", 50, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(account)
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("DELETE", fmt.Sprintf("/account/%d", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// An archived account is not found until it is restored
+	notFoundTests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"Get", "GET", fmt.Sprintf("/account/%d", account.Id), ""},
+		{"Delete", "DELETE", fmt.Sprintf("/account/%d", account.Id), ""},
+		{"Patch", "PATCH", fmt.Sprintf("/account/%d", account.Id), `{"name": "Renamed Account"}`},
+		{"GetTags", "GET", fmt.Sprintf("/account/%d/tags", account.Id), ""},
+		{"AddTags", "POST", fmt.Sprintf("/account/%d/tags", account.Id), `{"tags": ["archived"]}`},
+		{"RemoveTag", "DELETE", fmt.Sprintf("/account/%d/tags/archived", account.Id), ""},
+		{"GetBalanceHistory", "GET", fmt.Sprintf("/account/%d/balance-history", account.Id), ""},
+		{"GetTransactions", "GET", fmt.Sprintf("/account/%d/transactions", account.Id), ""},
+		{"GetUtxos", "GET", fmt.Sprintf("/account/%d/utxos", account.Id), ""},
+		{"Refresh", "POST", fmt.Sprintf("/account/%d/refresh", account.Id), ""},
+		{"GetByAddress", "GET", fmt.Sprintf("/account/by-address/%s", archiveTestAddress), ""},
+	}
+	for _, notFoundTest := range notFoundTests {
+		t.Run("TestArchivedAccountRoute"+notFoundTest.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest(notFoundTest.method, notFoundTest.path, bytes.NewBufferString(notFoundTest.body))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, http.StatusNotFound, response.Code)
+
+			var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+			assert.Equal(t, "Account not found", responseDto.Message)
+		})
+	}
+
+	assert.Nil(t, database.GetAccountById(nil, account.Id))
+	accountAfter := database.GetAccountByIdIncludeDeleted(nil, account.Id)
+	assert.True(t, accountAfter.IsDeleted())
+	assert.Equal(t, "Archived Account", accountAfter.Name)
+	assert.Equal(t, 0, len(database.GetAccountTags(nil, account.Id)))
+}
@@ -0,0 +1,143 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/test"
+	"go-gin-test-job/test/seeds"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func validationGetAccountBalanceHistoryTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		path         string
+		query        url.Values
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidId",
+			"/account/0/balance-history",
+			url.Values{},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"},
+		},
+		{
+			"FailInvalidCountValue",
+			"/account/1/balance-history",
+			url.Values{"count": []string{"101"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Count must be less than or equal 100"},
+		},
+		{
+			"FailInvalidFromType",
+			"/account/1/balance-history",
+			url.Values{"from": []string{"yesterday"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "from is invalid"},
+		},
+		{
+			"FailInvalidRange",
+			"/account/1/balance-history",
+			url.Values{"from": []string{"20"}, "to": []string{"10"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "From must be less than or equal To"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountBalanceHistoryRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     validationTest.path,
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountBalanceHistoryRoute_FailNotFound(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/account/999999/balance-history", nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestGetAccountBalanceHistoryRoute_SuccessParamsFromAndTo(t *testing.T) {
+	account := seeds.ACCOUNTS.ACCOUNT_1
+	// Snapshots far in the past so they do not mix with the ones written by the cron
+	histories := []*entities.AccountBalanceHistory{
+		entities.CreateAccountBalanceHistory(account.Id, decimal.Zero, decimal.RequireFromString("1.5"), "bitcore", 1000),
+		entities.CreateAccountBalanceHistory(account.Id, decimal.RequireFromString("1.5"), decimal.RequireFromString("0.5"), "bitcore", 2000),
+		entities.CreateAccountBalanceHistory(account.Id, decimal.RequireFromString("0.5"), decimal.RequireFromString("2"), "bitcore", 3000),
+	}
+	for _, history := range histories {
+		assert.Nil(t, database.CreateAccountBalanceHistory(nil, history))
+	}
+
+	query := url.Values{}
+	query.Add("from", "1500")
+	query.Add("to", "3000")
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account/%d/balance-history", account.Id),
+		RawQuery: query.Encode(),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountBalanceHistoryResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(2), responseDto.Total)
+	if assert.Equal(t, 2, len(responseDto.List)) {
+		// Newest first
+		assert.Equal(t, histories[2].Id, responseDto.List[0].Id)
+		assert.Equal(t, "1.5", responseDto.List[0].Delta)
+		assert.Equal(t, histories[1].Id, responseDto.List[1].Id)
+		assert.Equal(t, "-1", responseDto.List[1].Delta)
+		assert.Equal(t, "0.5", responseDto.List[1].NewBalance)
+	}
+
+	// Clean up
+	for _, history := range histories {
+		database.DbConn.Delete(history)
+	}
+}
@@ -0,0 +1,153 @@
+package accountTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/test"
+	"go-gin-test-job/test/seeds"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var bulkTestAddresses = []string{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", "12c6DSiU4Rq3P4ZxziKxzrGuvYEcGyzjPj"}
+
+func validationBulkCreateAccountTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		jsonParams   string
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailMissingItems",
+			`{"mode": "atomic"}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Items must contain at least 1 item"},
+		},
+		{
+			"FailInvalidMode",
+			`{"mode": "invalid mode", "items": [{"address": "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", "name": "Test Account", "rank": 50, "status": "On"}]}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Mode must be one of the next values: atomic,bestEffort"},
+		},
+		{
+			"FailTooManyItems",
+			`{"items": [` + strings.TrimSuffix(strings.Repeat(`{"address": "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},`, 501), ",") + `]}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Items must contain at most 500 items"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestBulkCreateAccountRoute"+validationTest.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/account/bulk", bytes.NewBufferString(validationTest.jsonParams))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func getBulkCreateAccountTestItems() []accountModuleDto.PostCreateAccountRequestDto {
+	return []accountModuleDto.PostCreateAccountRequestDto{
+		{Address: bulkTestAddresses[0], Name: "Bulk Account 1", Rank: 10, Status: entities.AccountStatusOn},
+		{Address: seeds.ACCOUNTS.ACCOUNT_1.Address, Name: "Bulk Account Conflict", Rank: 20, Status: entities.AccountStatusOn},
+		{Address: "wrong address", Name: "Bulk Account Invalid", Rank: 30, Status: entities.AccountStatusOn},
+		{Address: bulkTestAddresses[1], Name: "Bulk Account 2", Rank: 40, Status: entities.AccountStatusOff},
+	}
+}
+
+func sendBulkCreateAccountRequest(t *testing.T, params accountModuleDto.PostBulkCreateAccountRequestDto) accountModuleDto.PostBulkCreateAccountResponseDto {
+	body, _ := json.Marshal(params)
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/account/bulk", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.PostBulkCreateAccountResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func TestBulkCreateAccountRoute_SuccessAtomicRollback(t *testing.T) {
+	// Clean up any existing test accounts
+	database.DbConn.Where("address IN (?)", bulkTestAddresses).Delete(&entities.Account{})
+
+	responseDto := sendBulkCreateAccountRequest(t, accountModuleDto.PostBulkCreateAccountRequestDto{
+		Mode:  accountModuleDto.BulkCreateAccountModeAtomic,
+		Items: getBulkCreateAccountTestItems(),
+	})
+
+	assert.Equal(t, accountModuleDto.BulkCreateAccountModeAtomic, responseDto.Mode)
+	assert.Equal(t, 0, responseDto.Created)
+	assert.Equal(t, 4, responseDto.Failed)
+	assert.Equal(t, 4, len(responseDto.Results))
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusRolledBack, responseDto.Results[0].Status)
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusConflict, responseDto.Results[1].Status)
+	assert.Equal(t, "Address already exists", responseDto.Results[1].Message)
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusValidationError, responseDto.Results[2].Status)
+	assert.Equal(t, "Address format is wrong", responseDto.Results[2].Message)
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusRolledBack, responseDto.Results[3].Status)
+
+	// Nothing is created
+	for _, address := range bulkTestAddresses {
+		assert.Nil(t, database.GetAccountByAddress(address))
+	}
+}
+
+func TestBulkCreateAccountRoute_SuccessBestEffort(t *testing.T) {
+	// Clean up any existing test accounts
+	database.DbConn.Where("address IN (?)", bulkTestAddresses).Delete(&entities.Account{})
+
+	items := getBulkCreateAccountTestItems()
+	responseDto := sendBulkCreateAccountRequest(t, accountModuleDto.PostBulkCreateAccountRequestDto{
+		Items: items,
+	})
+
+	assert.Equal(t, accountModuleDto.BulkCreateAccountModeBestEffort, responseDto.Mode)
+	assert.Equal(t, 2, responseDto.Created)
+	assert.Equal(t, 2, responseDto.Failed)
+	assert.Equal(t, 4, len(responseDto.Results))
+	for index, result := range responseDto.Results {
+		assert.Equal(t, index, result.Index)
+		assert.Equal(t, items[index].Address, result.Address)
+	}
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusCreated, responseDto.Results[0].Status)
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusConflict, responseDto.Results[1].Status)
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusValidationError, responseDto.Results[2].Status)
+	assert.Equal(t, accountModuleDto.BulkCreateAccountItemStatusCreated, responseDto.Results[3].Status)
+
+	for _, index := range []int{0, 3} {
+		account := database.GetAccountByAddress(items[index].Address)
+		assert.NotNil(t, account)
+		assert.NotNil(t, responseDto.Results[index].Account)
+		assert.Equal(t, account.Id, responseDto.Results[index].Account.Id)
+		assert.Equal(t, items[index].Name, responseDto.Results[index].Account.Name)
+		assert.Equal(t, items[index].Rank, responseDto.Results[index].Account.Rank)
+		assert.Equal(t, string(items[index].Status), responseDto.Results[index].Account.Status)
+	}
+
+	// Clean up
+	database.DbConn.Where("address IN (?)", bulkTestAddresses).Delete(&entities.Account{})
+}
@@ -0,0 +1,159 @@
+package accountTests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	orderUtil "go-gin-test-job/src/utils/order"
+	"go-gin-test-job/test"
+	"go-gin-test-job/test/seeds"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+const importTestAddress = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+
+func TestExportAccountsRoute_FailInvalidFormat(t *testing.T) {
+	query := url.Values{}
+	query.Add("format", "xlsx")
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account/export"),
+		RawQuery: query.Encode(),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Format must be one of the next values: csv", responseDto.Message)
+}
+
+func TestExportAccountsRoute_SuccessParamsStatusAndOrderBy(t *testing.T) {
+	type Params struct {
+		Status  entities.AccountStatus `json:"status"`
+		OrderBy string                 `json:"orderBy"`
+	}
+	params := &Params{
+		Status:  entities.AccountStatusOn,
+		OrderBy: "id DESC",
+	}
+
+	query := url.Values{}
+	query.Add("format", "csv")
+	query.Add("status", string(params.Status))
+	query.Add("orderBy", params.OrderBy)
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account/export"),
+		RawQuery: query.Encode(),
+	}
+
+	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, params.OrderBy, ",", accountModuleDto.GetAvailableAccountSortFieldList)
+	assert.Nil(t, err)
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{Status: params.Status}, orderParams, 0, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+
+	// Read the response body and parse CSV
+	records, err := csv.NewReader(response.Body).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, int(total)+1, len(records))
+	assert.Equal(t, accountModuleDto.AccountCsvColumns, records[0])
+
+	for index, account := range accounts {
+		record := records[index+1]
+		assert.Equal(t, strconv.FormatInt(account.Id, 10), record[0])
+		assert.Equal(t, account.Address, record[1])
+		assert.Equal(t, account.Name, record[2])
+		assert.Equal(t, account.Balance.String(), record[5])
+		assert.Equal(t, string(params.Status), record[6])
+	}
+}
+
+func TestImportAccountsRoute_FailMissingColumn(t *testing.T) {
+	response := sendImportAccountsRequest(t, "address,name,status\n")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Column rank is required", responseDto.Message)
+}
+
+func TestImportAccountsRoute_Success(t *testing.T) {
+	// Clean up any existing test accounts
+	database.DbConn.Where("address = ?", importTestAddress).Delete(&entities.Account{})
+
+	content := "address,name,rank,memo,status\n" +
+		importTestAddress + ",Imported Account,40,Imported from CSV,On\n" +
+		seeds.ACCOUNTS.ACCOUNT_1.Address + ",Duplicate Account,40,,On\n" +
+		"wrong address,Invalid Account,40,,On\n" +
+		"1BoatSLRHtKNngkdXEeobR76b53LETtpyT,Invalid Rank,rank,,On\n"
+	response := sendImportAccountsRequest(t, content)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.PostImportAccountResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, responseDto.Accepted)
+	assert.Equal(t, 3, responseDto.Rejected)
+	assert.Equal(t, 2, responseDto.AcceptedRows[0].Line)
+	assert.Equal(t, importTestAddress, responseDto.AcceptedRows[0].Account.Address)
+	assert.Equal(t, "Imported from CSV", *responseDto.AcceptedRows[0].Account.Memo)
+	assert.Equal(t, 3, responseDto.RejectedRows[0].Line)
+	assert.Equal(t, "Conflict error. Address already exists", responseDto.RejectedRows[0].Message)
+	assert.Equal(t, 4, responseDto.RejectedRows[1].Line)
+	assert.Equal(t, "Address format is wrong", responseDto.RejectedRows[1].Message)
+	assert.Equal(t, 5, responseDto.RejectedRows[2].Line)
+	assert.Equal(t, "Rank must be between 0 and 100", responseDto.RejectedRows[2].Message)
+
+	assert.NotNil(t, database.GetAccountByAddress(importTestAddress))
+
+	// Clean up
+	database.DbConn.Where("address = ?", importTestAddress).Delete(&entities.Account{})
+}
+
+func sendImportAccountsRequest(t *testing.T, content string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "accounts.csv")
+	assert.Nil(t, err)
+	_, err = part.Write([]byte(content))
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/account/import", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	return response
+}
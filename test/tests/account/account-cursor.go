@@ -0,0 +1,140 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	cursorUtil "go-gin-test-job/src/utils/cursor"
+	numberUtil "go-gin-test-job/src/utils/number"
+	orderUtil "go-gin-test-job/src/utils/order"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func validationGetAccountsCursorTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		query        url.Values
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidCursor",
+			url.Values{"cursor": []string{"invalid cursor"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Cursor is invalid"},
+		},
+		{
+			"FailCursorWithOffset",
+			url.Values{"cursor": []string{cursorUtil.Encode("id ASC", []string{"1"})}, "offset": []string{"1"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Offset cannot be used with cursor"},
+		},
+		{
+			"FailCursorOrderByMismatch",
+			url.Values{"cursor": []string{cursorUtil.Encode("id ASC", []string{"1"})}, "orderBy": []string{"name ASC"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Cursor does not match orderBy"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountsRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     fmt.Sprintf("/account"),
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountsRoute_SuccessParamsCursor(t *testing.T) {
+	type Params struct {
+		Count   int    `json:"count"`
+		OrderBy string `json:"orderBy"`
+	}
+	params := &Params{
+		Count:   1,
+		OrderBy: "rank DESC",
+	}
+
+	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, params.OrderBy, ",", accountModuleDto.GetAvailableAccountSortFieldList)
+	assert.Nil(t, err)
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{}, orderParams, 0, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
+	assert.Greater(t, total, int64(params.Count))
+
+	// Walk through all pages following nextCursor
+	pageAccountIds := make([]int64, 0)
+	cursor := ""
+	for page := 0; page <= len(accounts); page++ {
+		query := url.Values{}
+		query.Add("count", numberUtil.IntToString(params.Count))
+		query.Add("orderBy", params.OrderBy)
+		if cursor != "" {
+			query.Add("cursor", cursor)
+		}
+		u := &url.URL{
+			Path:     fmt.Sprintf("/account"),
+			RawQuery: query.Encode(),
+		}
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", u.String(), nil)
+		request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+		test.TestApp.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		// Read the response body and parse JSON
+		body := response.Body.Bytes()
+		var responseDto accountModuleDto.GetAccountCursorResponseDto
+		err := json.Unmarshal(body, &responseDto)
+		assert.Nil(t, err)
+
+		// Only the first page is in offset mode and counts the total
+		if cursor == "" {
+			var offsetResponseDto accountModuleDto.GetAccountResponseDto
+			err = json.Unmarshal(body, &offsetResponseDto)
+			assert.Nil(t, err)
+			assert.Equal(t, total, offsetResponseDto.Total)
+		} else {
+			var fields map[string]interface{}
+			err = json.Unmarshal(body, &fields)
+			assert.Nil(t, err)
+			assert.NotContains(t, fields, "total")
+			assert.NotContains(t, fields, "offset")
+		}
+		assert.LessOrEqual(t, len(responseDto.List), params.Count)
+		for _, accountDto := range responseDto.List {
+			pageAccountIds = append(pageAccountIds, accountDto.Id)
+		}
+		if responseDto.NextCursor == nil {
+			break
+		}
+		cursor = *responseDto.NextCursor
+	}
+
+	assert.Equal(t, len(accounts), len(pageAccountIds))
+	for index, account := range accounts {
+		assert.Equal(t, account.Id, pageAccountIds[index])
+	}
+}
@@ -0,0 +1,164 @@
+package accountTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func validationPatchAccountTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		jsonParams   string
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailEmptyPayload",
+			`{}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "At least one field must be provided"},
+		},
+		{
+			"FailAddressChange",
+			`{"address": "1JzfdUygUFk2M6KS3ngFMGRsy5vsH4N37a"}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Address cannot be changed"},
+		},
+		{
+			"FailEmptyName",
+			`{"name": "  "}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Name must not be empty"},
+		},
+		{
+			"FailInvalidRank",
+			`{"rank": 101}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Rank must be between 0 and 100"},
+		},
+		{
+			"FailInvalidRefreshIntervalSec",
+			`{"refreshIntervalSec": -1}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "RefreshIntervalSec must be between 0 and 604800"},
+		},
+		{
+			"FailInvalidAccountStatus",
+			`{"status": "invalid status"}`,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: fmt.Sprintf("%s must be one of the next values: %s", "Status", strings.Join(entities.AccountStatusList, ","))},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestPatchAccountRoute"+validationTest.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("PATCH", "/account/1", bytes.NewBufferString(validationTest.jsonParams))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestPatchAccountRoute_FailNotFound(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("PATCH", "/account/999999", bytes.NewBufferString(`{"name": "Renamed Account"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestPatchAccountRoute_Success(t *testing.T) {
+	// Clean up any existing test accounts
+	database.DbConn.Where("address = ?", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT").Delete(&entities.Account{})
+
+	memo := "Account before update"
+	account := entities.Account{
+		Address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT",
+		Name:    "Test Account",
+		Rank:    50,
+		Memo:    &memo,
+		Status:  entities.AccountStatusOn,
+	}
+	result := database.DbConn.Create(&account)
+	assert.Nil(t, result.Error)
+
+	type Params struct {
+		Name               *string                 `json:"name,omitempty"`
+		Rank               *int8                   `json:"rank,omitempty"`
+		Status             *entities.AccountStatus `json:"status,omitempty"`
+		RefreshIntervalSec *int                    `json:"refreshIntervalSec,omitempty"`
+	}
+	name := "Renamed Account"
+	rank := int8(10)
+	status := entities.AccountStatusOff
+	refreshIntervalSec := 600
+	params := &Params{
+		Name:               &name,
+		Rank:               &rank,
+		Status:             &status,
+		RefreshIntervalSec: &refreshIntervalSec,
+	}
+
+	body, _ := json.Marshal(params)
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("PATCH", fmt.Sprintf("/account/%d", account.Id), bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.AccountDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, account.Id, responseDto.Id)
+	assert.Equal(t, account.Address, responseDto.Address)
+	assert.Equal(t, name, responseDto.Name)
+	assert.Equal(t, rank, responseDto.Rank)
+	assert.Equal(t, memo, *responseDto.Memo)
+	assert.Equal(t, string(status), responseDto.Status)
+	assert.Equal(t, refreshIntervalSec, responseDto.RefreshIntervalSec)
+
+	accountAfter := database.GetAccountById(nil, account.Id)
+	assert.NotNil(t, accountAfter)
+	assert.Equal(t, name, accountAfter.Name)
+	assert.Equal(t, rank, accountAfter.Rank)
+	assert.Equal(t, memo, *accountAfter.Memo)
+	assert.Equal(t, status, accountAfter.Status)
+	assert.Equal(t, refreshIntervalSec, accountAfter.RefreshIntervalSec)
+
+	// Clean up
+	database.DbConn.Delete(&account)
+}
@@ -0,0 +1,186 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	orderUtil "go-gin-test-job/src/utils/order"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func validationGetAccountsRangeFilterTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		query        url.Values
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidRankMinValue",
+			url.Values{"rankMin": []string{"-1"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "RankMin must be between 0 and 100"},
+		},
+		{
+			"FailInvalidRankRange",
+			url.Values{"rankMin": []string{"50"}, "rankMax": []string{"10"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "RankMin must be less than or equal RankMax"},
+		},
+		{
+			"FailInvalidBalanceMin",
+			url.Values{"balanceMin": []string{"one"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "BalanceMin must be a decimal number"},
+		},
+		{
+			"FailInvalidBalanceRange",
+			url.Values{"balanceMin": []string{"2"}, "balanceMax": []string{"1.5"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "BalanceMin must be less than or equal BalanceMax"},
+		},
+		{
+			"FailInvalidCreatedRange",
+			url.Values{"createdFrom": []string{"20"}, "createdTo": []string{"10"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "CreatedFrom must be less than or equal CreatedTo"},
+		},
+		{
+			"FailInvalidUpdatedToValue",
+			url.Values{"updatedTo": []string{"-10"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "UpdatedTo must be greater than or equal 0"},
+		},
+		{
+			"FailInvalidPendingFunds",
+			url.Values{"includeDeleted": []string{"true"}, "pendingFunds": []string{"maybe"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "pendingFunds is invalid"},
+		},
+		{
+			"FailInvalidIncludeDeleted",
+			url.Values{"includeDeleted": []string{"maybe"}, "pendingFunds": []string{"true"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "includeDeleted is invalid"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountsRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     fmt.Sprintf("/account"),
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountsRoute_SuccessParamsRangeFilters(t *testing.T) {
+	type Params struct {
+		Status     entities.AccountStatus `json:"status"`
+		RankMin    int8                   `json:"rankMin"`
+		BalanceMin string                 `json:"balanceMin"`
+		OrderBy    string                 `json:"orderBy"`
+	}
+	params := &Params{
+		Status:     entities.AccountStatusOn,
+		RankMin:    80,
+		BalanceMin: "0.5",
+		OrderBy:    "balance DESC",
+	}
+
+	query := url.Values{}
+	query.Add("status", string(params.Status))
+	query.Add("rankMin", fmt.Sprintf("%d", params.RankMin))
+	query.Add("balanceMin", params.BalanceMin)
+	query.Add("orderBy", params.OrderBy)
+
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account"),
+		RawQuery: query.Encode(),
+	}
+
+	balanceMin := decimal.RequireFromString(params.BalanceMin)
+	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, params.OrderBy, ",", accountModuleDto.GetAvailableAccountSortFieldList)
+	assert.Nil(t, err)
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{Status: params.Status, RankMin: &params.RankMin, BalanceMin: &balanceMin}, orderParams, accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
+	assert.Greater(t, total, int64(0))
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountResponseDto
+	err = json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, total, responseDto.Total)
+	assert.Equal(t, len(accounts), len(responseDto.List))
+	for index, accountDto := range responseDto.List {
+		test.CompareAccount(t, accounts[index], accountDto)
+		assert.Equal(t, string(params.Status), accountDto.Status)
+		assert.GreaterOrEqual(t, accountDto.Rank, params.RankMin)
+		assert.True(t, decimal.RequireFromString(accountDto.Balance).GreaterThanOrEqual(balanceMin))
+	}
+}
+
+func TestGetAccountsRoute_SuccessParamsPendingFunds(t *testing.T) {
+	query := url.Values{}
+	query.Add("pendingFunds", "true")
+	query.Add("orderBy", "id ASC")
+
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account"),
+		RawQuery: query.Encode(),
+	}
+
+	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, "id ASC", ",", accountModuleDto.GetAvailableAccountSortFieldList)
+	assert.Nil(t, err)
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{PendingFunds: true}, orderParams, accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
+	assert.Greater(t, total, int64(0))
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountResponseDto
+	err = json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, total, responseDto.Total)
+	assert.Equal(t, len(accounts), len(responseDto.List))
+	for index, accountDto := range responseDto.List {
+		test.CompareAccount(t, accounts[index], accountDto)
+		assert.True(t, decimal.RequireFromString(accountDto.UnconfirmedBalance).IsPositive())
+		assert.True(t, decimal.RequireFromString(accountDto.TotalBalance).GreaterThan(decimal.RequireFromString(accountDto.Balance)))
+	}
+}
@@ -0,0 +1,135 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	orderUtil "go-gin-test-job/src/utils/order"
+	"go-gin-test-job/test"
+	"go-gin-test-job/test/seeds"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func validationGetAccountsSearchTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		query        url.Values
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidSearchModeValue",
+			url.Values{"search": []string{"main"}, "searchMode": []string{"regexp"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "SearchMode must be one of the next values: contains,prefix,fulltext"},
+		},
+		{
+			"FailCursorWithFulltextRelevanceOrder",
+			url.Values{"search": []string{"account"}, "searchMode": []string{"fulltext"}, "cursor": []string{"eyJzIjoiaWQgQVNDIiwidiI6WyI1Il19"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Cursor requires orderBy in fulltext search mode"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountsRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     fmt.Sprintf("/account"),
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountsRoute_SuccessParamsSearchModePrefix(t *testing.T) {
+	search := "Reserve"
+	query := url.Values{}
+	query.Add("search", search)
+	query.Add("searchMode", string(entities.AccountSearchModePrefix))
+
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account"),
+		RawQuery: query.Encode(),
+	}
+
+	filter := database.AccountFilter{Search: search, SearchMode: entities.AccountSearchModePrefix}
+	accounts, total := database.GetAccountsAndTotal(filter, make([]orderUtil.OrderParam, 0), accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
+	assert.Greater(t, total, int64(0))
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, total, responseDto.Total)
+	assert.Equal(t, len(accounts), len(responseDto.List))
+	for index, accountDto := range responseDto.List {
+		test.CompareAccount(t, accounts[index], accountDto)
+		assert.True(t, strings.HasPrefix(accountDto.Address, search) || strings.HasPrefix(accountDto.Name, search))
+	}
+}
+
+func TestGetAccountsRoute_SuccessParamsSearchModeFulltext(t *testing.T) {
+	search := "transactions"
+	query := url.Values{}
+	query.Add("search", search)
+	query.Add("searchMode", string(entities.AccountSearchModeFulltext))
+
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account"),
+		RawQuery: query.Encode(),
+	}
+
+	filter := database.AccountFilter{Search: search, SearchMode: entities.AccountSearchModeFulltext}
+	accounts, total := database.GetAccountsAndTotal(filter, make([]orderUtil.OrderParam, 0), accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
+	assert.Greater(t, total, int64(0))
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, total, responseDto.Total)
+	assert.Nil(t, responseDto.NextCursor)
+	assert.Equal(t, len(accounts), len(responseDto.List))
+	for index, accountDto := range responseDto.List {
+		test.CompareAccount(t, accounts[index], accountDto)
+	}
+	if assert.NotEmpty(t, responseDto.List) {
+		assert.Equal(t, seeds.ACCOUNTS.ACCOUNT_2.Address, responseDto.List[0].Address)
+	}
+}
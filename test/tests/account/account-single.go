@@ -0,0 +1,164 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/test"
+	"go-gin-test-job/test/seeds"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func validationGetAccountByIdTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		id           string
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidId",
+			"invalid",
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id is invalid"},
+		},
+		{
+			"FailInvalidIdMinValue",
+			"0",
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountByIdRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path: fmt.Sprintf("/account/%s", validationTest.id),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountByIdRoute_FailNotFound(t *testing.T) {
+	u := &url.URL{
+		Path: fmt.Sprintf("/account/%d", 999999),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestGetAccountByIdRoute_Success(t *testing.T) {
+	u := &url.URL{
+		Path: fmt.Sprintf("/account/%d", seeds.ACCOUNTS.ACCOUNT_1.Id),
+	}
+
+	account := database.GetAccountById(nil, seeds.ACCOUNTS.ACCOUNT_1.Id)
+	assert.NotNil(t, account)
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.AccountDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	test.CompareAccount(t, account, responseDto)
+}
+
+func TestGetAccountByAddressRoute_FailInvalidAddress(t *testing.T) {
+	u := &url.URL{
+		Path: fmt.Sprintf("/account/by-address/%s", "wrong-address"),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Address format is wrong", responseDto.Message)
+}
+
+func TestGetAccountByAddressRoute_FailNotFound(t *testing.T) {
+	u := &url.URL{
+		Path: fmt.Sprintf("/account/by-address/%s", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestGetAccountByAddressRoute_Success(t *testing.T) {
+	u := &url.URL{
+		Path: fmt.Sprintf("/account/by-address/%s", seeds.ACCOUNTS.ACCOUNT_2.Address),
+	}
+
+	account := database.GetAccountByAddress(seeds.ACCOUNTS.ACCOUNT_2.Address)
+	assert.NotNil(t, account)
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.AccountDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	test.CompareAccount(t, account, responseDto)
+}
@@ -0,0 +1,132 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	orderUtil "go-gin-test-job/src/utils/order"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func validationGetAccountStatsTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		query        url.Values
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidTopValue",
+			url.Values{"top": []string{"101"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Top must be less than or equal 100"},
+		},
+		{
+			"FailInvalidTopType",
+			url.Values{"top": []string{"five"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "top is invalid"},
+		},
+		{
+			"FailInvalidRankBucketSizeValue",
+			url.Values{"rankBucketSize": []string{"-1"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "RankBucketSize must be greater than or equal 1"},
+		},
+		{
+			"FailInvalidRankRange",
+			url.Values{"rankMin": []string{"50"}, "rankMax": []string{"10"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "RankMin must be less than or equal RankMax"},
+		},
+		{
+			"FailInvalidPendingFunds",
+			url.Values{"pendingFunds": []string{"maybe"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "pendingFunds is invalid"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountStatsRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     fmt.Sprintf("/account/stats"),
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountStatsRoute_Success(t *testing.T) {
+	top := 2
+	rankBucketSize := 25
+	query := url.Values{}
+	query.Add("top", fmt.Sprintf("%d", top))
+	query.Add("rankBucketSize", fmt.Sprintf("%d", rankBucketSize))
+
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account/stats"),
+		RawQuery: query.Encode(),
+	}
+
+	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, "balance DESC", ",", accountModuleDto.GetAvailableAccountSortFieldList)
+	assert.Nil(t, err)
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{}, orderParams, 0, 1000)
+	assert.Greater(t, total, int64(0))
+	totalBalance := decimal.Zero
+	for _, account := range accounts {
+		totalBalance = totalBalance.Add(account.Balance)
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountStatsResponseDto
+	err = json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, total, responseDto.Total)
+	assert.True(t, totalBalance.Equal(decimal.RequireFromString(responseDto.TotalBalance)))
+	var statusCount int64
+	for _, statusStatsDto := range responseDto.ByStatus {
+		statusCount += statusStatsDto.Count
+	}
+	assert.Equal(t, total, statusCount)
+	var histogramCount int64
+	for _, bucketDto := range responseDto.RankHistogram {
+		histogramCount += bucketDto.Count
+	}
+	assert.Equal(t, total, histogramCount)
+	assert.Equal(t, 5, len(responseDto.RankHistogram))
+	assert.Equal(t, min(top, len(accounts)), len(responseDto.TopByBalance))
+	for index, accountDto := range responseDto.TopByBalance {
+		test.CompareAccount(t, accounts[index], accountDto)
+	}
+	assert.NotNil(t, responseDto.OldestUpdatedAt)
+}
@@ -0,0 +1,196 @@
+package accountTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const tagsTestAddress = "1dice8EMZmqKvrGE4Qc9bUFf9PX3xaYDp"
+
+func validationAccountTagsTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		method       string
+		path         string
+		body         interface{}
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailEmptyTags",
+			"POST",
+			"/account/1/tags",
+			map[string]interface{}{"tags": []string{}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Tags must contain at least 1 item"},
+		},
+		{
+			"FailInvalidTag",
+			"POST",
+			"/account/1/tags",
+			map[string]interface{}{"tags": []string{"cold storage", "a,b"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Tag must not be empty, must be shorter than or equal to 64 characters and must not contain commas"},
+		},
+		{
+			"FailInvalidId",
+			"DELETE",
+			"/account/0/tags/exchange",
+			nil,
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestAccountTagsRoute"+validationTest.name, func(t *testing.T) {
+			var body []byte
+			if validationTest.body != nil {
+				body, _ = json.Marshal(validationTest.body)
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest(validationTest.method, validationTest.path, bytes.NewReader(body))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountTagsRoute_FailNotFound(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/account/999999/tags", nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestAccountTagsRoute_Success(t *testing.T) {
+	// Clean up any existing test accounts, their tags are removed by the foreign key
+	database.DbConn.Where("address = ?", tagsTestAddress).Delete(&entities.Account{})
+
+	account := entities.Account{
+		Address: tagsTestAddress,
+		Name:    "Tagged Account",
+		Rank:    40,
+		Status:  entities.AccountStatusOn,
+	}
+	result := database.DbConn.Create(&account)
+	assert.Nil(t, result.Error)
+
+	// Tags are normalized and duplicates are skipped
+	body, _ := json.Marshal(map[string]interface{}{
+		"tags": []string{"Cold Storage ", "exchange", "cold storage"},
+	})
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", fmt.Sprintf("/account/%d/tags", account.Id), bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var addResponseDto accountModuleDto.AccountTagsResponseDto
+	err := json.NewDecoder(response.Body).Decode(&addResponseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, account.Id, addResponseDto.AccountId)
+	assert.Equal(t, []string{"cold storage", "exchange"}, addResponseDto.Tags)
+
+	// Tags are included in the account
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("GET", fmt.Sprintf("/account/%d", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var accountDto accountModuleDto.AccountDto
+	err = json.NewDecoder(response.Body).Decode(&accountDto)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cold storage", "exchange"}, accountDto.Tags)
+
+	assert.True(t, isAccountInTagsList(t, account.Id, "cold storage,exchange", "all"))
+	assert.True(t, isAccountInTagsList(t, account.Id, "exchange,customer deposits", "any"))
+	assert.False(t, isAccountInTagsList(t, account.Id, "exchange,customer deposits", "all"))
+
+	// Remove one tag
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("DELETE", fmt.Sprintf("/account/%d/tags/exchange", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var removeResponseDto accountModuleDto.AccountTagsResponseDto
+	err = json.NewDecoder(response.Body).Decode(&removeResponseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cold storage"}, removeResponseDto.Tags)
+	assert.False(t, isAccountInTagsList(t, account.Id, "cold storage,exchange", "all"))
+
+	// The account no longer has the tag
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("DELETE", fmt.Sprintf("/account/%d/tags/exchange", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	var notFoundResponseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err = json.NewDecoder(response.Body).Decode(&notFoundResponseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, "Tag not found", notFoundResponseDto.Message)
+
+	// Clean up
+	database.DbConn.Where("address = ?", tagsTestAddress).Delete(&entities.Account{})
+}
+
+func isAccountInTagsList(t *testing.T, accountId int64, tags string, tagsMode string) bool {
+	query := url.Values{}
+	query.Add("tags", tags)
+	query.Add("tagsMode", tagsMode)
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account"),
+		RawQuery: query.Encode(),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto accountModuleDto.GetAccountResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	for _, accountDto := range responseDto.List {
+		if accountDto.Id == accountId {
+			return true
+		}
+	}
+	return false
+}
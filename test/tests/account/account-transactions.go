@@ -0,0 +1,152 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func validationGetAccountTransactionsTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		path         string
+		query        url.Values
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidId",
+			"/account/0/transactions",
+			url.Values{},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"},
+		},
+		{
+			"FailInvalidOffsetType",
+			"/account/1/transactions",
+			url.Values{"offset": []string{"first"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "offset is invalid"},
+		},
+		{
+			"FailInvalidCountValue",
+			"/account/1/transactions",
+			url.Values{"count": []string{"101"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Count must be less than or equal 100"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountTransactionsRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     validationTest.path,
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountTransactionsRoute_FailNotFound(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/account/999999/transactions", nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestGetAccountTransactionsRoute_SuccessParamsOffsetAndCount(t *testing.T) {
+	// A separate account so the transactions do not mix with the ones pulled by the cron
+	account, err := database.CreateAccount(nil, entities.CreateAccount("1TransactionsTestAccount", "Transactions Account", 10, nil, entities.AccountStatusOff))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(account)
+
+	height1, height2 := int64(800000), int64(800010)
+	time1, time2 := int64(1690000000), int64(1690006000)
+	transactions := []*entities.AccountTransaction{
+		entities.CreateAccountTransaction(account.Id, "tx-confirmed-old", &height1, &time1, decimal.RequireFromString("1.5"), decimal.Zero, decimal.RequireFromString("0.0001"), 11),
+		entities.CreateAccountTransaction(account.Id, "tx-unconfirmed", nil, nil, decimal.RequireFromString("0.2"), decimal.Zero, decimal.RequireFromString("0.00002"), 0),
+		entities.CreateAccountTransaction(account.Id, "tx-confirmed-new", &height2, &time2, decimal.Zero, decimal.RequireFromString("0.5"), decimal.RequireFromString("0.0003"), 1),
+	}
+	assert.Nil(t, database.UpsertAccountTransactions(nil, transactions))
+	// A repeated upsert updates the known transactions instead of adding them again
+	transactions[0].Confirmations = 12
+	assert.Nil(t, database.UpsertAccountTransactions(nil, transactions[:1]))
+
+	query := url.Values{}
+	query.Add("offset", "0")
+	query.Add("count", "2")
+	u := &url.URL{
+		Path:     fmt.Sprintf("/account/%d/transactions", account.Id),
+		RawQuery: query.Encode(),
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountTransactionsResponseDto
+	err = json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(3), responseDto.Total)
+	assert.Equal(t, 2, responseDto.Count)
+	if assert.Equal(t, 2, len(responseDto.List)) {
+		// Unconfirmed first, then the newest block
+		assert.Equal(t, "tx-unconfirmed", responseDto.List[0].Txid)
+		assert.Nil(t, responseDto.List[0].BlockHeight)
+		assert.Nil(t, responseDto.List[0].BlockTime)
+		assert.Equal(t, "0.2", responseDto.List[0].AmountIn)
+		assert.Equal(t, "tx-confirmed-new", responseDto.List[1].Txid)
+		assert.Equal(t, height2, *responseDto.List[1].BlockHeight)
+		assert.Equal(t, time2, *responseDto.List[1].BlockTime)
+		assert.Equal(t, "0.5", responseDto.List[1].AmountOut)
+		assert.Equal(t, "0.0003", responseDto.List[1].Fee)
+	}
+
+	storedTransactions, _ := database.GetAccountTransactionsAndTotal(account.Id, 2, 1)
+	if assert.Equal(t, 1, len(storedTransactions)) {
+		assert.Equal(t, "tx-confirmed-old", storedTransactions[0].Txid)
+		assert.Equal(t, int64(12), storedTransactions[0].Confirmations)
+	}
+	lastBlockHeight, err := database.GetAccountLastTransactionBlockHeight(account.Id)
+	assert.Nil(t, err)
+	if assert.NotNil(t, lastBlockHeight) {
+		assert.Equal(t, height2, *lastBlockHeight)
+	}
+}
@@ -0,0 +1,241 @@
+package accountTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	"go-gin-test-job/src/modules/common/blockchain"
+	orderUtil "go-gin-test-job/src/utils/order"
+	"go-gin-test-job/test"
+	"go-gin-test-job/test/seeds"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newUtxoStandInServer is a local stand-in for the Esplora api with a confirmed and a dust output per address
+func newUtxoStandInServer(failingAddresses ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blocks/tip/height" {
+			_, _ = fmt.Fprint(w, 800100)
+			return
+		}
+		address := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/address/"), "/utxo")
+		for _, failingAddress := range failingAddresses {
+			if address == failingAddress {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		_, _ = fmt.Fprintf(w, `[
+			{"txid": "%s-confirmed", "vout": 1, "value": 100000, "status": {"confirmed": true, "block_height": 800000}},
+			{"txid": "%s-dust", "vout": 0, "value": 500, "status": {"confirmed": false}}
+		]`, address, address)
+	}))
+}
+
+// useUtxoStandInServer replaces the resolver of the app until the returned restore function is called
+func useUtxoStandInServer(t *testing.T, server *httptest.Server) func() {
+	provider := blockchain.NewEsploraProvider(blockchain.NewApiClient(server.Client(), blockchain.ApiClientOptions{}), server.URL, blockchain.MainnetNetwork)
+	resolver, err := blockchain.NewBalanceResolver([]blockchain.BalanceProvider{provider}, 0)
+	assert.Nil(t, err)
+	defaultResolver := blockchain.Resolver
+	blockchain.Resolver = resolver
+	return func() {
+		blockchain.Resolver = defaultResolver
+	}
+}
+
+func validationGetAccountUtxosTests(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		path         string
+		query        url.Values
+		expectedCode int
+		expectedBody errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{
+			"FailInvalidId",
+			"/account/0/utxos",
+			url.Values{},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"},
+		},
+		{
+			"FailInvalidCountValue",
+			"/account/utxos",
+			url.Values{"count": []string{"101"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Count must be less than or equal 100"},
+		},
+		{
+			"FailInvalidDustThreshold",
+			"/account/utxos",
+			url.Values{"dustThreshold": []string{"dust"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "DustThreshold must be a decimal number"},
+		},
+		{
+			"FailDustOnlyWithoutThreshold",
+			"/account/utxos",
+			url.Values{"dustOnly": []string{"true"}},
+			http.StatusBadRequest,
+			errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "DustOnly requires DustThreshold"},
+		},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetAccountUtxosRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     validationTest.path,
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+
+			// Read the response body and parse JSON
+			var responseDto errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseDto)
+			assert.Nil(t, err)
+
+			assert.Equal(t, validationTest.expectedBody.Success, responseDto.Success)
+			assert.Equal(t, validationTest.expectedBody.Message, responseDto.Message)
+		})
+	}
+}
+
+func TestGetAccountUtxosRoute_FailNotFound(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/account/999999/utxos", nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseNotFoundErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Account not found", responseDto.Message)
+}
+
+func TestGetAccountUtxosRoute_FailProvider(t *testing.T) {
+	account := seeds.ACCOUNTS.ACCOUNT_1
+	server := newUtxoStandInServer(account.Address)
+	defer server.Close()
+	defer useUtxoStandInServer(t, server)()
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", fmt.Sprintf("/account/%d/utxos", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadGateway, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto errorHelpers.ResponseBadGatewayErrorHTTP
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, false, responseDto.Success)
+	assert.Equal(t, "Utxos are not available", responseDto.Message)
+}
+
+func TestGetAccountUtxosRoute_Success(t *testing.T) {
+	account := seeds.ACCOUNTS.ACCOUNT_1
+	server := newUtxoStandInServer()
+	defer server.Close()
+	defer useUtxoStandInServer(t, server)()
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", fmt.Sprintf("/account/%d/utxos", account.Id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto accountModuleDto.GetAccountUtxosResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+
+	assert.Equal(t, account.Id, responseDto.AccountId)
+	assert.Equal(t, account.Address, responseDto.Address)
+	assert.Equal(t, 2, responseDto.Count)
+	assert.Equal(t, "0.001005", responseDto.Value)
+	if assert.Equal(t, 2, len(responseDto.List)) {
+		assert.Equal(t, account.Address+"-confirmed", responseDto.List[0].Txid)
+		assert.Equal(t, int64(1), responseDto.List[0].Vout)
+		assert.Equal(t, "0.001", responseDto.List[0].Value)
+		assert.Equal(t, int64(101), responseDto.List[0].Confirmations)
+		assert.Equal(t, blockchain.ScriptTypeP2SH, responseDto.List[0].ScriptType)
+		assert.Equal(t, account.Address+"-dust", responseDto.List[1].Txid)
+		assert.Equal(t, "0.000005", responseDto.List[1].Value)
+		assert.Equal(t, int64(0), responseDto.List[1].Confirmations)
+	}
+}
+
+func TestGetAccountsUtxosRoute_SuccessParamsDustThreshold(t *testing.T) {
+	failingAccount := seeds.ACCOUNTS.ACCOUNT_2
+	server := newUtxoStandInServer(failingAccount.Address)
+	defer server.Close()
+	defer useUtxoStandInServer(t, server)()
+
+	status := entities.AccountStatusOn
+	orderParams := []orderUtil.OrderParam{{Field: "id", Direction: "ASC"}}
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{Status: status}, orderParams, accountModuleDto.DEFAULT_ACCOUNTS_UTXOS_OFFSET, accountModuleDto.DEFAULT_ACCOUNTS_UTXOS_COUNT)
+	assert.Greater(t, total, int64(0))
+
+	for _, dustOnly := range []bool{false, true} {
+		query := url.Values{}
+		query.Add("status", string(status))
+		query.Add("dustThreshold", "0.00001")
+		query.Add("dustOnly", fmt.Sprintf("%t", dustOnly))
+		u := &url.URL{
+			Path:     "/account/utxos",
+			RawQuery: query.Encode(),
+		}
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", u.String(), nil)
+		request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+		test.TestApp.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		// Read the response body and parse JSON
+		var responseDto accountModuleDto.GetAccountsUtxosResponseDto
+		err := json.NewDecoder(response.Body).Decode(&responseDto)
+		assert.Nil(t, err)
+
+		assert.Equal(t, total, responseDto.Total)
+		expectedUtxoCount := 0
+		for _, account := range accounts {
+			if account.Address == failingAccount.Address {
+				if assert.Equal(t, 1, len(responseDto.Failed)) {
+					assert.Equal(t, failingAccount.Id, responseDto.Failed[0].AccountId)
+				}
+				continue
+			}
+			expectedUtxoCount++
+		}
+		assert.Equal(t, expectedUtxoCount, responseDto.UtxoCount)
+		assert.Equal(t, expectedUtxoCount, len(responseDto.List))
+		value := decimal.Zero
+		for _, utxoDto := range responseDto.List {
+			// The dust output is the only one below the threshold
+			assert.Equal(t, dustOnly, strings.HasSuffix(utxoDto.Txid, "-dust"))
+			value = value.Add(decimal.RequireFromString(utxoDto.Value))
+		}
+		assert.Equal(t, value.String(), responseDto.Value)
+	}
+}
@@ -29,10 +29,64 @@ func TestAccountRoute(t *testing.T) {
 	t.Run("TestGetAccountsRoute_SuccessParamsOrderBy", TestGetAccountsRoute_SuccessParamsOrderBy)
 	t.Run("TestGetAccountsRoute_SuccessParamsStatusAndOrderBy", TestGetAccountsRoute_SuccessParamsStatusAndOrderBy)
 	t.Run("TestGetAccountsRoute_SuccessParamsOffsetAndCountAndStatusAndOrderBy", TestGetAccountsRoute_SuccessParamsOffsetAndCountAndStatusAndOrderBy)
+	validationGetAccountsCursorTests(t)
+	t.Run("TestGetAccountsRoute_SuccessParamsCursor", TestGetAccountsRoute_SuccessParamsCursor)
+	validationGetAccountsRangeFilterTests(t)
+	t.Run("TestGetAccountsRoute_SuccessParamsRangeFilters", TestGetAccountsRoute_SuccessParamsRangeFilters)
+	t.Run("TestGetAccountsRoute_SuccessParamsPendingFunds", TestGetAccountsRoute_SuccessParamsPendingFunds)
+	validationGetAccountsSearchTests(t)
+	t.Run("TestGetAccountsRoute_SuccessParamsSearchModePrefix", TestGetAccountsRoute_SuccessParamsSearchModePrefix)
+	t.Run("TestGetAccountsRoute_SuccessParamsSearchModeFulltext", TestGetAccountsRoute_SuccessParamsSearchModeFulltext)
+	// GetAccountStats
+	validationGetAccountStatsTests(t)
+	t.Run("TestGetAccountStatsRoute_Success", TestGetAccountStatsRoute_Success)
+	// ExportAccounts and ImportAccounts
+	t.Run("TestExportAccountsRoute_FailInvalidFormat", TestExportAccountsRoute_FailInvalidFormat)
+	t.Run("TestExportAccountsRoute_SuccessParamsStatusAndOrderBy", TestExportAccountsRoute_SuccessParamsStatusAndOrderBy)
+	t.Run("TestImportAccountsRoute_FailMissingColumn", TestImportAccountsRoute_FailMissingColumn)
+	t.Run("TestImportAccountsRoute_Success", TestImportAccountsRoute_Success)
+	// GetAccountById
+	validationGetAccountByIdTests(t)
+	t.Run("TestGetAccountByIdRoute_FailNotFound", TestGetAccountByIdRoute_FailNotFound)
+	t.Run("TestGetAccountByIdRoute_Success", TestGetAccountByIdRoute_Success)
+	// GetAccountByAddress
+	t.Run("TestGetAccountByAddressRoute_FailInvalidAddress", TestGetAccountByAddressRoute_FailInvalidAddress)
+	t.Run("TestGetAccountByAddressRoute_FailNotFound", TestGetAccountByAddressRoute_FailNotFound)
+	t.Run("TestGetAccountByAddressRoute_Success", TestGetAccountByAddressRoute_Success)
+	// PatchAccount
+	validationPatchAccountTests(t)
+	t.Run("TestPatchAccountRoute_FailNotFound", TestPatchAccountRoute_FailNotFound)
+	t.Run("TestPatchAccountRoute_Success", TestPatchAccountRoute_Success)
+	// DeleteAccount and RestoreAccount
+	t.Run("TestDeleteAccountRoute_FailNotFound", TestDeleteAccountRoute_FailNotFound)
+	t.Run("TestDeleteAndRestoreAccountRoute_Success", TestDeleteAndRestoreAccountRoute_Success)
+	t.Run("TestArchivedAccountRoute_FailNotFound", TestArchivedAccountRoute_FailNotFound)
+	// GetAccountTags, AddAccountTags and RemoveAccountTag
+	validationAccountTagsTests(t)
+	t.Run("TestGetAccountTagsRoute_FailNotFound", TestGetAccountTagsRoute_FailNotFound)
+	t.Run("TestAccountTagsRoute_Success", TestAccountTagsRoute_Success)
+	// GetAccountBalanceHistory
+	validationGetAccountBalanceHistoryTests(t)
+	t.Run("TestGetAccountBalanceHistoryRoute_FailNotFound", TestGetAccountBalanceHistoryRoute_FailNotFound)
+	t.Run("TestGetAccountBalanceHistoryRoute_SuccessParamsFromAndTo", TestGetAccountBalanceHistoryRoute_SuccessParamsFromAndTo)
+	// GetAccountTransactions
+	validationGetAccountTransactionsTests(t)
+	t.Run("TestGetAccountTransactionsRoute_FailNotFound", TestGetAccountTransactionsRoute_FailNotFound)
+	t.Run("TestGetAccountTransactionsRoute_SuccessParamsOffsetAndCount", TestGetAccountTransactionsRoute_SuccessParamsOffsetAndCount)
+	// GetAccountUtxos and GetAccountsUtxos
+	validationGetAccountUtxosTests(t)
+	t.Run("TestGetAccountUtxosRoute_FailNotFound", TestGetAccountUtxosRoute_FailNotFound)
+	t.Run("TestGetAccountUtxosRoute_FailProvider", TestGetAccountUtxosRoute_FailProvider)
+	t.Run("TestGetAccountUtxosRoute_Success", TestGetAccountUtxosRoute_Success)
+	t.Run("TestGetAccountsUtxosRoute_SuccessParamsDustThreshold", TestGetAccountsUtxosRoute_SuccessParamsDustThreshold)
 	// CreateAccount
 	validationCreateAccountTests(t)
 	t.Run("TestCreateAccountRoute_FailAddressAlreadyExists", TestCreateAccountRoute_FailAddressAlreadyExists)
 	t.Run("TestCreateAccountRoute_Success", TestCreateAccountRoute_Success)
+	// BulkCreateAccounts
+	validationBulkCreateAccountTests(t)
+	t.Run("TestBulkCreateAccountRoute_SuccessAtomicRollback", TestBulkCreateAccountRoute_SuccessAtomicRollback)
+	t.Run("TestBulkCreateAccountRoute_SuccessBestEffort", TestBulkCreateAccountRoute_SuccessBestEffort)
 }
 
 func validationGetAccountsTests(t *testing.T) {
@@ -139,7 +193,7 @@ func TestGetAccountsRoute_SuccessNoParams(t *testing.T) {
 		Path: fmt.Sprintf("/account"),
 	}
 
-	accounts, total := database.GetAccountsAndTotal("", make(map[string]string), accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT, "")
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{}, make([]orderUtil.OrderParam, 0), accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
 
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest("GET", u.String(), nil)
@@ -194,7 +248,7 @@ func TestGetAccountsRoute_SuccessParamsOffsetAndCount(t *testing.T) {
 		RawQuery: query.Encode(),
 	}
 
-	accounts, total := database.GetAccountsAndTotal("", make(map[string]string), params.Offset, params.Count, "")
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{}, make([]orderUtil.OrderParam, 0), params.Offset, params.Count)
 
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest("GET", u.String(), nil)
@@ -246,7 +300,7 @@ func TestGetAccountsRoute_SuccessParamsStatus(t *testing.T) {
 		RawQuery: query.Encode(),
 	}
 
-	accounts, total := database.GetAccountsAndTotal(params.Status, make(map[string]string), accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT, "")
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{Status: params.Status}, make([]orderUtil.OrderParam, 0), accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
 
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest("GET", u.String(), nil)
@@ -299,7 +353,7 @@ func TestGetAccountsRoute_SuccessParamsOrderBy(t *testing.T) {
 	}
 
 	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, params.OrderBy, ",", accountModuleDto.GetAvailableAccountSortFieldList)
-	accounts, total := database.GetAccountsAndTotal("", orderParams, accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT, "")
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{}, orderParams, accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
 
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest("GET", u.String(), nil)
@@ -355,7 +409,7 @@ func TestGetAccountsRoute_SuccessParamsStatusAndOrderBy(t *testing.T) {
 	}
 
 	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, params.OrderBy, ",", accountModuleDto.GetAvailableAccountSortFieldList)
-	accounts, total := database.GetAccountsAndTotal(params.Status, orderParams, accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT, "")
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{Status: params.Status}, orderParams, accountModuleDto.DEFAULT_ACCOUNT_OFFSET, accountModuleDto.DEFAULT_ACCOUNT_COUNT)
 
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest("GET", u.String(), nil)
@@ -417,7 +471,7 @@ func TestGetAccountsRoute_SuccessParamsOffsetAndCountAndStatusAndOrderBy(t *test
 	}
 
 	orderParams, err := orderUtil.GetOrderByParamsSecure(nil, params.OrderBy, ",", accountModuleDto.GetAvailableAccountSortFieldList)
-	accounts, total := database.GetAccountsAndTotal(params.Status, orderParams, params.Offset, params.Count, "")
+	accounts, total := database.GetAccountsAndTotal(database.AccountFilter{Status: params.Status}, orderParams, params.Offset, params.Count)
 
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest("GET", u.String(), nil)
@@ -0,0 +1,267 @@
+package apiKeyTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	apiKeyModuleDto "go-gin-test-job/src/modules/api-key/dto"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestApiKeyRoute(t *testing.T) {
+	t.Run("TestCreateApiKeyRoute_Success", TestCreateApiKeyRoute_Success)
+	t.Run("TestApiKeyGuard_SuccessScopes", TestApiKeyGuard_SuccessScopes)
+	t.Run("TestRevokeApiKeyRoute_Success", TestRevokeApiKeyRoute_Success)
+	t.Run("TestApiKeyGuard_FailExpired", TestApiKeyGuard_FailExpired)
+	t.Run("TestApiKeyGuard_FailUnknownKey", TestApiKeyGuard_FailUnknownKey)
+	t.Run("TestApiKeyRoute_Fail", TestApiKeyRoute_Fail)
+}
+
+func serveWithApiKey(method string, path string, body string, apiKey string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", apiKey)
+	test.TestApp.ServeHTTP(response, request)
+	return response
+}
+
+func createApiKey(t *testing.T, body string) apiKeyModuleDto.PostCreateApiKeyResponseDto {
+	response := serveWithApiKey("POST", "/api-key", body, config.AppConfig.AdminXApiKey)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto apiKeyModuleDto.PostCreateApiKeyResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func getApiKeys(t *testing.T, query url.Values) apiKeyModuleDto.GetApiKeysResponseDto {
+	u := &url.URL{
+		Path:     "/api-key",
+		RawQuery: query.Encode(),
+	}
+	response := serveWithApiKey("GET", u.String(), "", config.AppConfig.AdminXApiKey)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto apiKeyModuleDto.GetApiKeysResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func findApiKey(list []apiKeyModuleDto.ApiKeyDto, id int64) *apiKeyModuleDto.ApiKeyDto {
+	for index := range list {
+		if list[index].Id == id {
+			return &list[index]
+		}
+	}
+	return nil
+}
+
+func TestCreateApiKeyRoute_Success(t *testing.T) {
+	start := timeUtil.GetUnixTime()
+	// The scopes are stored without duplicates in the order of the scope list
+	created := createApiKey(t, `{"name": " Billing service ", "scopes": ["cron:run", "account:read"], "expiresInSec": 3600}`)
+	assert.Greater(t, created.Id, int64(0))
+	assert.Equal(t, "Billing service", created.Name)
+	assert.Equal(t, []string{entities.ApiKeyScopeAccountRead, entities.ApiKeyScopeCronRun}, created.Scopes)
+	assert.Equal(t, true, created.Active)
+	assert.Equal(t, 64, len(created.Key))
+	assert.Equal(t, created.Key[:entities.ApiKeyPrefixLength], created.Prefix)
+	if assert.NotNil(t, created.ExpiresAt) {
+		assert.GreaterOrEqual(t, *created.ExpiresAt, start+3600)
+	}
+	assert.Nil(t, created.LastUsedAt)
+	assert.Nil(t, created.RevokedAt)
+
+	// Only the hash of the key is stored
+	stored := database.GetApiKeyById(nil, created.Id)
+	if assert.NotNil(t, stored) {
+		assert.NotEqual(t, created.Key, stored.KeyHash)
+		assert.Equal(t, entities.HashApiKey(created.Key), stored.KeyHash)
+	}
+
+	// The key is never returned again
+	response := serveWithApiKey("GET", "/api-key", "", config.AppConfig.AdminXApiKey)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), created.Key)
+	apiKeys := getApiKeys(t, url.Values{})
+	listed := findApiKey(apiKeys.List, created.Id)
+	if assert.NotNil(t, listed) {
+		assert.Equal(t, created.ApiKeyDto, *listed)
+	}
+	assert.GreaterOrEqual(t, apiKeys.Total, int64(1))
+
+	// A key without an expiry never expires
+	created = createApiKey(t, `{"name": "Forever", "scopes": ["account:read"]}`)
+	assert.Nil(t, created.ExpiresAt)
+}
+
+func TestApiKeyGuard_SuccessScopes(t *testing.T) {
+	created := createApiKey(t, `{"name": "Reader", "scopes": ["account:read", "cron:read"]}`)
+
+	response := serveWithApiKey("GET", "/account", "", created.Key)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = serveWithApiKey("GET", "/cron/runs", "", created.Key)
+	assert.Equal(t, http.StatusOK, response.Code)
+	// The job is read with the read scope, so the guard lets the key in to the missing job
+	response = serveWithApiKey("GET", "/cron/jobs/999999999", "", created.Key)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// The key is used, so its last use is stored
+	stored := database.GetApiKeyById(nil, created.Id)
+	if assert.NotNil(t, stored) {
+		assert.Greater(t, stored.LastUsedAt, int64(0))
+	}
+
+	// Without the scope the key is forbidden
+	forbiddenTests := []struct {
+		method string
+		path   string
+		scope  string
+	}{
+		{"POST", "/account", entities.ApiKeyScopeAccountWrite},
+		{"POST", "/cron/account-balance", entities.ApiKeyScopeCronRun},
+		{"GET", "/api-key", entities.ApiKeyScopeApiKeyManage},
+	}
+	for _, forbiddenTest := range forbiddenTests {
+		response = serveWithApiKey(forbiddenTest.method, forbiddenTest.path, "{}", created.Key)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+
+		var responseBody errorHelpers.ResponseForbiddenErrorHTTP
+		err := json.NewDecoder(response.Body).Decode(&responseBody)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("Api key does not have the %s scope", forbiddenTest.scope), responseBody.Message)
+	}
+
+	// A key that only runs the cron does not read the jobs
+	runner := createApiKey(t, `{"name": "Runner", "scopes": ["cron:run"]}`)
+	response = serveWithApiKey("GET", "/cron/jobs/999999999", "", runner.Key)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	// The static cron key only runs the cron and reads its runs
+	response = serveWithApiKey("GET", "/account", "", config.AppConfig.CronXApiKey)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	response = serveWithApiKey("GET", "/cron/jobs/999999999", "", config.AppConfig.CronXApiKey)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestRevokeApiKeyRoute_Success(t *testing.T) {
+	created := createApiKey(t, `{"name": "Rotated", "scopes": ["account:read"]}`)
+	response := serveWithApiKey("GET", "/account", "", created.Key)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	response = serveWithApiKey("DELETE", fmt.Sprintf("/api-key/%d", created.Id), "", config.AppConfig.AdminXApiKey)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var revoked apiKeyModuleDto.ApiKeyDto
+	err := json.NewDecoder(response.Body).Decode(&revoked)
+	assert.Nil(t, err)
+	assert.Equal(t, created.Id, revoked.Id)
+	assert.Equal(t, false, revoked.Active)
+	assert.NotNil(t, revoked.RevokedAt)
+
+	// The revoked key stops working right away
+	response = serveWithApiKey("GET", "/account", "", created.Key)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// The revoked keys are listed only on request
+	assert.Nil(t, findApiKey(getApiKeys(t, url.Values{"count": []string{"100"}}).List, created.Id))
+	listed := findApiKey(getApiKeys(t, url.Values{"count": []string{"100"}, "includeRevoked": []string{"true"}}).List, created.Id)
+	if assert.NotNil(t, listed) {
+		assert.Equal(t, revoked, *listed)
+	}
+
+	response = serveWithApiKey("DELETE", fmt.Sprintf("/api-key/%d", created.Id), "", config.AppConfig.AdminXApiKey)
+	assert.Equal(t, http.StatusConflict, response.Code)
+}
+
+func TestApiKeyGuard_FailExpired(t *testing.T) {
+	apiKey, key, err := entities.CreateApiKey("Expired", []string{entities.ApiKeyScopeAccountRead}, timeUtil.GetUnixTime()-1)
+	assert.Nil(t, err)
+	assert.Nil(t, database.CreateApiKey(nil, apiKey))
+
+	response := serveWithApiKey("GET", "/account", "", key)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	listed := findApiKey(getApiKeys(t, url.Values{"count": []string{"100"}}).List, apiKey.Id)
+	if assert.NotNil(t, listed) {
+		assert.Equal(t, false, listed.Active)
+	}
+
+	// A key with the right prefix and a wrong rest does not match
+	other, otherKey, err := entities.CreateApiKey("Other", []string{entities.ApiKeyScopeAccountRead}, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, database.CreateApiKey(nil, other))
+	response = serveWithApiKey("GET", "/account", "", otherKey[:entities.ApiKeyPrefixLength]+key[entities.ApiKeyPrefixLength:])
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	response = serveWithApiKey("GET", "/account", "", otherKey)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestApiKeyGuard_FailUnknownKey(t *testing.T) {
+	apiKey, key, err := entities.CreateApiKey("Unknown", []string{entities.ApiKeyScopeAccountRead}, 0)
+	assert.Nil(t, err)
+	response := serveWithApiKey("GET", "/account", "", key)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// The unknown key is not looked up again for a while, even if it is stored in the meantime
+	assert.Nil(t, database.CreateApiKey(nil, apiKey))
+	defer database.DbConn.Delete(apiKey)
+	response = serveWithApiKey("GET", "/account", "", key)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// A key shorter than the prefix is not looked up at all
+	response = serveWithApiKey("GET", "/account", "", key[:entities.ApiKeyPrefixLength-1])
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestApiKeyRoute_Fail(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		apiKey       string
+		expectedCode int
+		expectedBody *errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{"FailUnauthorized", "GET", "/api-key", "", "", http.StatusUnauthorized, nil},
+		{"FailUnknownKey", "GET", "/account", "", "unknown-api-key", http.StatusUnauthorized, nil},
+		{"FailCronKey", "POST", "/api-key", `{"name": "Key", "scopes": ["account:read"]}`, config.AppConfig.CronXApiKey, http.StatusForbidden, nil},
+		{"FailNameRequired", "POST", "/api-key", `{"name": " ", "scopes": ["account:read"]}`, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Name is required"}},
+		{"FailNameTooLong", "POST", "/api-key", fmt.Sprintf(`{"name": "%065d", "scopes": ["account:read"]}`, 0), config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Name must be shorter than or equal to 64 characters"}},
+		{"FailScopesEmpty", "POST", "/api-key", `{"name": "Key", "scopes": []}`, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Scopes must contain at least 1 item"}},
+		{"FailScopesDuplicate", "POST", "/api-key", `{"name": "Key", "scopes": ["account:read", "account:read"]}`, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Scopes must not contain duplicates"}},
+		{"FailUnknownScope", "POST", "/api-key", `{"name": "Key", "scopes": ["account:delete"]}`, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Every scope must be one of the next values: account:read,account:write,cron:run,cron:read,api-key:manage"}},
+		{"FailExpiresInSec", "POST", "/api-key", `{"name": "Key", "scopes": ["account:read"], "expiresInSec": -1}`, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "ExpiresInSec must be between 0 and 315360000"}},
+		{"FailInvalidBody", "POST", "/api-key", `{"name": 1}`, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Invalid request query"}},
+		{"FailInvalidCount", "GET", "/api-key?count=101", "", config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Count must be less than or equal 100"}},
+		{"FailInvalidIncludeRevoked", "GET", "/api-key?includeRevoked=yes", "", config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "includeRevoked is invalid"}},
+		{"FailInvalidId", "DELETE", "/api-key/0", "", config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"}},
+		{"FailNotFound", "DELETE", "/api-key/999999999", "", config.AppConfig.AdminXApiKey, http.StatusNotFound, nil},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestApiKeyRoute"+validationTest.name, func(t *testing.T) {
+			response := serveWithApiKey(validationTest.method, validationTest.path, validationTest.body, validationTest.apiKey)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+			if validationTest.expectedBody == nil {
+				return
+			}
+
+			var responseBody errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseBody)
+			assert.Nil(t, err)
+			assert.Equal(t, *validationTest.expectedBody, responseBody)
+		})
+	}
+}
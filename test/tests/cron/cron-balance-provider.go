@@ -0,0 +1,405 @@
+package cronTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	arrayUtil "go-gin-test-job/src/utils/array"
+	currencyUtil "go-gin-test-job/src/utils/currency"
+	numberUtil "go-gin-test-job/src/utils/number"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// esploraStandInTipHeight is the chain tip of the Esplora stand-in, its transactions are in the block esploraStandInBlockHeight
+const esploraStandInTipHeight = 800100
+const esploraStandInBlockHeight = 800000
+
+// newEsploraStandInServer is a local stand-in for the Esplora api, the balances are in satoshi
+func newEsploraStandInServer(balances map[string]int64) *httptest.Server {
+	return httptest.NewServer(newEsploraStandInHandler(balances))
+}
+
+func newEsploraStandInHandler(balances map[string]int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blocks/tip/height" {
+			_, _ = fmt.Fprint(w, esploraStandInTipHeight)
+			return
+		}
+		address := strings.TrimPrefix(r.URL.Path, "/address/")
+		if strings.HasSuffix(address, "/txs") {
+			address = strings.TrimSuffix(address, "/txs")
+			balance, exists := balances[address]
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `[
+				{"txid": "%s-mempool", "fee": 200, "status": {"confirmed": false}, "vin": [{"prevout": {"scriptpubkey_address": "other", "value": 1000}}], "vout": [{"scriptpubkey_address": "%s", "value": 777}]},
+				{"txid": "%s-block", "fee": 100, "status": {"confirmed": true, "block_height": %d, "block_time": 1690000000}, "vin": [{"prevout": {"scriptpubkey_address": "%s", "value": 5000}}], "vout": [{"scriptpubkey_address": "%s", "value": %d}]}
+			]`, address, address, address, esploraStandInBlockHeight, address, address, balance+5000)
+			return
+		}
+		balance, exists := balances[address]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// 5000 satoshi are spent and 777 are unconfirmed, neither is in the confirmed balance
+		_, _ = fmt.Fprintf(w, `{"chain_stats": {"funded_txo_sum": %d, "spent_txo_sum": 5000}, "mempool_stats": {"funded_txo_sum": 777, "spent_txo_sum": 0}}`, balance+5000)
+	}
+}
+
+func getRandomAccountsBalances(accounts []*entities.Account) map[string]int64 {
+	balances := make(map[string]int64)
+	for _, account := range accounts {
+		balances[account.Address] = int64(numberUtil.GetRandomNumber(0, 10000000000))
+	}
+	return balances
+}
+
+// testApiClientOptions keep the retries short so that the tests do not wait for the backoff
+var testApiClientOptions = blockchain.ApiClientOptions{
+	MaxRetries:     2,
+	RetryBaseDelay: time.Millisecond,
+	RetryMaxDelay:  100 * time.Millisecond,
+}
+
+func newBalanceResolver(t *testing.T, servers []*httptest.Server, quorum int) *blockchain.BalanceResolver {
+	providers := make([]blockchain.BalanceProvider, 0, len(servers))
+	for _, server := range servers {
+		apiClient := blockchain.NewApiClient(server.Client(), testApiClientOptions)
+		providers = append(providers, blockchain.NewEsploraProvider(apiClient, server.URL, blockchain.MainnetNetwork))
+	}
+	resolver, err := blockchain.NewBalanceResolver(providers, quorum)
+	assert.Nil(t, err)
+	return resolver
+}
+
+// useBalanceResolver replaces the resolver of the app until the returned restore function is called
+func useBalanceResolver(t *testing.T, servers []*httptest.Server, quorum int) func() {
+	resolver := newBalanceResolver(t, servers, quorum)
+	defaultResolver := blockchain.Resolver
+	blockchain.Resolver = resolver
+	return func() {
+		blockchain.Resolver = defaultResolver
+	}
+}
+
+func runUpdateAccountsBalances(t *testing.T) cronModuleDto.UpdateAccountsBalancesResponseDto {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/cron/account-balance", nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.CronXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Read the response body and parse JSON
+	var responseDto cronModuleDto.UpdateAccountsBalancesResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, true, responseDto.Success)
+	return responseDto
+}
+
+func assertAccountsBalances(t *testing.T, accounts []*entities.Account, balances map[string]int64, source string, start int64) {
+	for _, account := range accounts {
+		accountAfter := database.GetAccountById(nil, account.Id)
+		assert.NotNil(t, accountAfter)
+		assert.Equal(t, currencyUtil.FromSatoshi(balances[account.Address]).String(), accountAfter.Balance.String())
+		assert.Equal(t, currencyUtil.FromSatoshi(777).String(), accountAfter.UnconfirmedBalance.String())
+		assert.Equal(t, currencyUtil.FromSatoshi(balances[account.Address]+777).String(), accountAfter.TotalBalance.String())
+
+		// The accounts are the ones before the run, an unchanged confirmed balance writes no snapshot
+		histories, _ := database.GetAccountBalanceHistoryAndTotal(database.AccountBalanceHistoryFilter{AccountId: account.Id, CreatedFrom: &start}, 0, 1)
+		if account.Balance.Equal(accountAfter.Balance) {
+			assert.Equal(t, 0, len(histories))
+		} else if assert.Equal(t, 1, len(histories)) {
+			assert.Equal(t, source, histories[0].Source)
+			assert.Equal(t, accountAfter.Balance.String(), histories[0].NewBalance.String())
+		}
+	}
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessBitcoreProvider(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	balances := getRandomAccountsBalances(accountsBefore)
+	for _, accountBefore := range accountsBefore {
+		balance := balances[accountBefore.Address]
+		httpmock.RegisterResponder(
+			"GET",
+			fmt.Sprintf("https://api.bitcore.io/api/BTC/mainnet/address/%s/balance", accountBefore.Address),
+			httpmock.NewStringResponder(200, fmt.Sprintf(`{"confirmed": %d, "unconfirmed": 777, "balance": %d}`, balance, balance+777)),
+		)
+		// One coin received by a confirmed transaction and not spent yet, it is in every page of the coins and in the unspent ones
+		mintTxid := fmt.Sprintf("%s-mint", accountBefore.Address)
+		coins := httpmock.NewStringResponder(200, fmt.Sprintf(`[{"_id": "1", "mintTxid": "%s", "mintHeight": 700000, "spentTxid": "", "spentHeight": -2, "value": %d}]`, mintTxid, balance))
+		httpmock.RegisterResponder("GET", fmt.Sprintf("https://api.bitcore.io/api/BTC/mainnet/address/%s/txs", accountBefore.Address), coins)
+		httpmock.RegisterResponder("GET", fmt.Sprintf("https://api.bitcore.io/api/BTC/mainnet/address/%s", accountBefore.Address), coins)
+		httpmock.RegisterResponder(
+			"GET",
+			fmt.Sprintf("https://api.bitcore.io/api/BTC/mainnet/tx/%s", mintTxid),
+			httpmock.NewStringResponder(200, fmt.Sprintf(`{"txid": "%s", "blockHeight": 700000, "blockTimeNormalized": "2021-09-11T04:14:32.000Z", "fee": 1000, "confirmations": 10}`, mintTxid)),
+		)
+	}
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, 0, responseDto.Failed)
+	assert.Equal(t, 0, responseDto.TransactionsFailed)
+	assertAccountsBalances(t, accountsBefore, balances, blockchain.BitcoreProviderName, start)
+
+	// The transactions are pulled in the same pass
+	for _, accountBefore := range accountsBefore {
+		transactions, _ := database.GetAccountTransactionsAndTotal(accountBefore.Id, 0, 100)
+		transaction := arrayUtil.FindItem(transactions, []func(transaction *entities.AccountTransaction) bool{
+			func(transaction *entities.AccountTransaction) bool {
+				return transaction.Txid == accountBefore.Address+"-mint"
+			},
+		})
+		if assert.NotNil(t, transaction) {
+			assert.Equal(t, int64(700000), *(*transaction).BlockHeight)
+			assert.Equal(t, int64(1631333672), *(*transaction).BlockTime)
+			assert.Equal(t, currencyUtil.FromSatoshi(balances[accountBefore.Address]).String(), (*transaction).AmountIn.String())
+			assert.Equal(t, "0.00001", (*transaction).Fee.String())
+			assert.Equal(t, int64(10), (*transaction).Confirmations)
+		}
+	}
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessEsploraProvider(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	balances := getRandomAccountsBalances(accountsBefore)
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, len(accountsBefore), responseDto.Updated)
+	assert.Equal(t, 0, responseDto.Failed)
+	assertAccountsBalances(t, accountsBefore, balances, blockchain.EsploraProviderName, start)
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessFailover(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	// The first provider is down, the second one answers
+	downServer := newEsploraStandInServer(map[string]int64{})
+	downServer.Close()
+	balances := getRandomAccountsBalances(accountsBefore)
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{downServer, server}, 0)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, len(accountsBefore), responseDto.Updated)
+	assert.Equal(t, 0, responseDto.Failed)
+	assert.Equal(t, 0, len(responseDto.Disagreements))
+	assertAccountsBalances(t, accountsBefore, balances, blockchain.EsploraProviderName, start)
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessQuorum(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	// Two of three providers agree
+	balances := getRandomAccountsBalances(accountsBefore)
+	staleBalances := getRandomAccountsBalances(accountsBefore)
+	servers := []*httptest.Server{newEsploraStandInServer(staleBalances), newEsploraStandInServer(balances), newEsploraStandInServer(balances)}
+	for _, server := range servers {
+		defer server.Close()
+	}
+	defer useBalanceResolver(t, servers, 2)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, len(accountsBefore), responseDto.Updated)
+	assert.Equal(t, 0, responseDto.Failed)
+	assert.Equal(t, len(accountsBefore), len(responseDto.Disagreements))
+	for _, disagreementDto := range responseDto.Disagreements {
+		assert.True(t, disagreementDto.Written)
+		assert.Equal(t, 3, len(disagreementDto.Balances))
+	}
+	assertAccountsBalances(t, accountsBefore, balances, blockchain.EsploraProviderName+","+blockchain.EsploraProviderName, start)
+}
+
+func TestUpdateAccountsBalancesRoute_FailQuorumDisagreement(t *testing.T) {
+	defer useImmediateRefresh()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	// Both providers answer with different balances, so nothing is written
+	servers := []*httptest.Server{newEsploraStandInServer(getRandomAccountsBalances(accountsBefore)), newEsploraStandInServer(getRandomAccountsBalances(accountsBefore))}
+	for _, server := range servers {
+		defer server.Close()
+	}
+	defer useBalanceResolver(t, servers, 2)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, 0, responseDto.Updated)
+	assert.Equal(t, len(accountsBefore), responseDto.Failed)
+	assert.Equal(t, len(accountsBefore), len(responseDto.Disagreements))
+	for _, disagreementDto := range responseDto.Disagreements {
+		assert.False(t, disagreementDto.Written)
+	}
+	for _, accountBefore := range accountsBefore {
+		accountAfter := database.GetAccountById(nil, accountBefore.Id)
+		assert.Equal(t, accountBefore.Balance.String(), accountAfter.Balance.String())
+		assert.Equal(t, accountBefore.UpdatedAt, accountAfter.UpdatedAt)
+	}
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessRetry(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	// Every address gets a 503 and then a 429 before the balance
+	balances := getRandomAccountsBalances(accountsBefore)
+	standInServer := newEsploraStandInServer(balances)
+	defer standInServer.Close()
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.URL.Path]++
+		attempt := attempts[r.URL.Path]
+		mu.Unlock()
+		switch attempt {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = fmt.Fprint(w, "<html><body>Service Unavailable</body></html>")
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			standInServer.Config.Handler.ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, len(accountsBefore), responseDto.Updated)
+	assert.Equal(t, 0, responseDto.Failed)
+	assertAccountsBalances(t, accountsBefore, balances, blockchain.EsploraProviderName, start)
+	for _, account := range accountsBefore {
+		assert.Equal(t, 3, attempts["/address/"+account.Address])
+	}
+}
+
+func TestUpdateAccountsBalancesRoute_FailInvalidResponse(t *testing.T) {
+	defer useImmediateRefresh()()
+	responses := map[string]http.HandlerFunc{
+		"Empty": func(w http.ResponseWriter, r *http.Request) {},
+		"Html": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, "<html><body>Maintenance</body></html>")
+		},
+		"NoStats": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, `{}`)
+		},
+		"NegativeBalance": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, `{"chain_stats": {"funded_txo_sum": 1000, "spent_txo_sum": 5000}}`)
+		},
+		"NotFound": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"confirmed": 0}`)
+		},
+		"RetryAfterTooLong": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		"ServerError": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	}
+	for name, handler := range responses {
+		t.Run(name, func(t *testing.T) {
+			accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+			assert.Greater(t, len(accountsBefore), 0)
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+			// The stored balances are kept
+			responseDto := runUpdateAccountsBalances(t)
+			assert.Equal(t, 0, responseDto.Updated)
+			assert.Equal(t, len(accountsBefore), responseDto.Failed)
+			for _, accountBefore := range accountsBefore {
+				accountAfter := database.GetAccountById(nil, accountBefore.Id)
+				assert.Equal(t, accountBefore.Balance.String(), accountAfter.Balance.String())
+				assert.Equal(t, accountBefore.UpdatedAt, accountAfter.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessTransactions(t *testing.T) {
+	defer useImmediateRefresh()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	balances := getRandomAccountsBalances(accountsBefore)
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	// The second run pulls the same transactions again, they are updated and not added twice
+	for run := 0; run < 2; run++ {
+		responseDto := runUpdateAccountsBalances(t)
+		assert.Equal(t, 2*len(accountsBefore), responseDto.Transactions)
+		assert.Equal(t, 0, responseDto.TransactionsFailed)
+	}
+
+	for _, account := range accountsBefore {
+		transactions, total := database.GetAccountTransactionsAndTotal(account.Id, 0, 100)
+		assert.Equal(t, int64(2), total)
+		if !assert.Equal(t, 2, len(transactions)) {
+			continue
+		}
+		// Unconfirmed first
+		assert.Equal(t, account.Address+"-mempool", transactions[0].Txid)
+		assert.False(t, transactions[0].IsConfirmed())
+		assert.Equal(t, currencyUtil.FromSatoshi(777).String(), transactions[0].AmountIn.String())
+		assert.Equal(t, "0", transactions[0].AmountOut.String())
+		assert.Equal(t, int64(0), transactions[0].Confirmations)
+
+		assert.Equal(t, account.Address+"-block", transactions[1].Txid)
+		if assert.True(t, transactions[1].IsConfirmed()) {
+			assert.Equal(t, int64(esploraStandInBlockHeight), *transactions[1].BlockHeight)
+			assert.Equal(t, int64(1690000000), *transactions[1].BlockTime)
+		}
+		assert.Equal(t, currencyUtil.FromSatoshi(balances[account.Address]+5000).String(), transactions[1].AmountIn.String())
+		assert.Equal(t, currencyUtil.FromSatoshi(5000).String(), transactions[1].AmountOut.String())
+		assert.Equal(t, currencyUtil.FromSatoshi(100).String(), transactions[1].Fee.String())
+		assert.Equal(t, int64(esploraStandInTipHeight-esploraStandInBlockHeight+1), transactions[1].Confirmations)
+	}
+}
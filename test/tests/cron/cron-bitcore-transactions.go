@@ -0,0 +1,163 @@
+package cronTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	currencyUtil "go-gin-test-job/src/utils/currency"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bitcoreStandInHeights are the blocks of the transactions of the Bitcore stand-in, -1 is the mempool
+var bitcoreStandInHeights = map[string]int64{
+	"tx-100-a":   100,
+	"tx-100-b":   100,
+	"tx-101":     101,
+	"tx-102":     102,
+	"tx-mempool": -1,
+}
+
+// bitcoreStandInCoins are the coins of the address of the Bitcore stand-in, the coin of tx-100-a is spent by tx-102
+var bitcoreStandInCoins = []blockchain.BitcoreCoin{
+	{Id: "1", MintTxid: "tx-102", MintIndex: 0, MintHeight: 102, SpentHeight: -2, Value: 4000},
+	{Id: "2", MintTxid: "tx-100-a", MintIndex: 0, MintHeight: 100, SpentTxid: "tx-102", SpentHeight: 102, Value: 5000},
+	{Id: "3", MintTxid: "tx-mempool", MintIndex: 1, MintHeight: -1, SpentHeight: -2, Value: 500},
+	{Id: "4", MintTxid: "tx-101", MintIndex: 0, MintHeight: 101, SpentHeight: -2, Value: 2000},
+	{Id: "5", MintTxid: "tx-100-b", MintIndex: 2, MintHeight: 100, SpentHeight: -2, Value: 1000},
+}
+
+// newBitcoreStandInServer is a local stand-in for the Bitcore api with one address
+func newBitcoreStandInServer(address string, requests map[string]int, requestsLock *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/BTC/mainnet")
+		query := r.URL.Query()
+		switch {
+		case path == fmt.Sprintf("/address/%s/balance", address):
+			_, _ = fmt.Fprint(w, `{"confirmed": 7000, "unconfirmed": 500, "balance": 7500}`)
+		case path == fmt.Sprintf("/address/%s/txs", address):
+			paging := query.Get("paging")
+			since, err := strconv.ParseInt(query.Get("since"), 10, 64)
+			if (paging != "mintHeight" && paging != "spentHeight") || query.Get("direction") != "1" || err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			requestsLock.Lock()
+			requests[fmt.Sprintf("%s>%d", paging, since)]++
+			requestsLock.Unlock()
+			coins := make([]blockchain.BitcoreCoin, 0)
+			for _, coin := range bitcoreStandInCoins {
+				height := coin.MintHeight
+				if paging == "spentHeight" {
+					height = coin.SpentHeight
+				}
+				if height > since {
+					coins = append(coins, coin)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(coins)
+		case path == fmt.Sprintf("/address/%s", address) && query.Get("unspent") == "true":
+			coins := make([]blockchain.BitcoreCoin, 0)
+			for _, coin := range bitcoreStandInCoins {
+				if coin.SpentHeight < 0 {
+					coins = append(coins, coin)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(coins)
+		case strings.HasPrefix(path, "/tx/"):
+			txid := strings.TrimPrefix(path, "/tx/")
+			requestsLock.Lock()
+			requests[txid]++
+			requestsLock.Unlock()
+			_, _ = fmt.Fprintf(w, `{"txid": "%s", "blockHeight": %d, "blockTimeNormalized": "2023-07-22T00:00:00.000Z", "fee": 100, "confirmations": 1}`, txid, bitcoreStandInHeights[txid])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func refreshAccount(t *testing.T, id int64) cronModuleDto.RefreshAccountResponseDto {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", fmt.Sprintf("/account/%d/refresh", id), nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto cronModuleDto.RefreshAccountResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func TestRefreshAccountRoute_SuccessBitcoreTransactionsPerRun(t *testing.T) {
+	defer useImmediateRefresh()()
+	account, err := database.CreateAccount(nil, entities.CreateAccount("1RefreshBitcoreAccount", "Bitcore Account", 10, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(account)
+
+	requests := make(map[string]int)
+	var requestsLock sync.Mutex
+	server := newBitcoreStandInServer(account.Address, requests, &requestsLock)
+	defer server.Close()
+	// One transaction per run, but a block is always read to the end
+	provider := blockchain.NewBitcoreProvider(blockchain.NewApiClient(server.Client(), testApiClientOptions), server.URL, blockchain.MainnetNetwork, 1)
+	resolver, err := blockchain.NewBalanceResolver([]blockchain.BalanceProvider{provider}, 0)
+	assert.Nil(t, err)
+	defaultResolver := blockchain.Resolver
+	blockchain.Resolver = resolver
+	defer func() {
+		blockchain.Resolver = defaultResolver
+	}()
+
+	// Every run reads the last stored block again and goes one block further, the mempool comes last
+	runs := []struct {
+		transactions int
+		stored       int64
+	}{
+		{2, 2},
+		{3, 3},
+		{2, 4},
+		{2, 5},
+	}
+	for _, run := range runs {
+		responseDto := refreshAccount(t, account.Id)
+		assert.Equal(t, true, responseDto.Success)
+		assert.Equal(t, blockchain.BitcoreProviderName, responseDto.Source)
+		assert.Equal(t, run.transactions, responseDto.Transactions)
+		assert.Nil(t, responseDto.TransactionsError)
+		_, total := database.GetAccountTransactionsAndTotal(account.Id, 0, 100)
+		assert.Equal(t, run.stored, total)
+	}
+	// The coins are read only from the last stored block
+	assert.Equal(t, map[string]int{
+		"mintHeight>-1": 1, "mintHeight>99": 1, "mintHeight>100": 1, "mintHeight>101": 1,
+		"spentHeight>-1": 1, "spentHeight>99": 1, "spentHeight>100": 1, "spentHeight>101": 1,
+		"tx-100-a": 2, "tx-100-b": 2, "tx-101": 2, "tx-102": 2, "tx-mempool": 1,
+	}, requests)
+
+	transactions, _ := database.GetAccountTransactionsAndTotal(account.Id, 0, 100)
+	if !assert.Equal(t, 5, len(transactions)) {
+		return
+	}
+	assert.Equal(t, "tx-mempool", transactions[0].Txid)
+	assert.False(t, transactions[0].IsConfirmed())
+	assert.Equal(t, currencyUtil.FromSatoshi(500).String(), transactions[0].AmountIn.String())
+	assert.Equal(t, "tx-102", transactions[1].Txid)
+	if assert.True(t, transactions[1].IsConfirmed()) {
+		assert.Equal(t, int64(102), *transactions[1].BlockHeight)
+	}
+	assert.Equal(t, currencyUtil.FromSatoshi(4000).String(), transactions[1].AmountIn.String())
+	assert.Equal(t, currencyUtil.FromSatoshi(5000).String(), transactions[1].AmountOut.String())
+	assert.Equal(t, "tx-101", transactions[2].Txid)
+}
@@ -0,0 +1,230 @@
+package cronTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	cronModule "go-gin-test-job/src/modules/cron"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func getCronJobById(t *testing.T, id int64) cronModuleDto.GetCronJobResponseDto {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", fmt.Sprintf("/cron/jobs/%d", id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.CronXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto cronModuleDto.GetCronJobResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessAsync(t *testing.T) {
+	defer useImmediateRefresh()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	if !assert.Greater(t, len(accountsBefore), 0) {
+		return
+	}
+
+	// The stand-in holds the job until it is released
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	releaseJob := func() {
+		releaseOnce.Do(func() {
+			close(release)
+		})
+	}
+	handler := newEsploraStandInHandler(getRandomAccountsBalances(accountsBefore))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		handler(w, r)
+	}))
+	defer server.Close()
+	// Released before the server is closed, a failed test does not leave the job waiting
+	defer releaseJob()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/cron/account-balance?async=true", nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.CronXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+
+	var responseDto cronModuleDto.UpdateAccountsBalancesAsyncResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, true, responseDto.Success)
+	assert.Greater(t, responseDto.JobId, int64(0))
+	assert.Equal(t, entities.CronRunStatusRunning, responseDto.Status)
+	assert.Equal(t, len(accountsBefore), responseDto.Total)
+
+	job := getCronJobById(t, responseDto.JobId)
+	assert.Equal(t, entities.CronRunStatusRunning, job.Status)
+	assert.Equal(t, false, job.Finished)
+	assert.Equal(t, len(accountsBefore), job.Total)
+	assert.Equal(t, 0, job.Processed)
+	assert.Equal(t, int64(0), job.FinishedAt)
+
+	// The job holds the lock until it is finished
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest("POST", "/cron/account-balance?async=true", nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.CronXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	releaseJob()
+	assert.Eventually(t, func() bool {
+		return database.GetCronRunById(responseDto.JobId).IsFinished()
+	}, 10*time.Second, 50*time.Millisecond)
+	cronModule.WaitAsyncRuns()
+
+	job = getCronJobById(t, responseDto.JobId)
+	assert.Equal(t, entities.CronRunStatusSuccess, job.Status)
+	assert.Equal(t, true, job.Finished)
+	assert.Equal(t, len(accountsBefore), job.Total)
+	assert.Equal(t, len(accountsBefore), job.Processed)
+	assert.Equal(t, 0, job.Failed)
+	assert.Equal(t, 0, len(job.Errors))
+	assert.GreaterOrEqual(t, job.FinishedAt, job.StartedAt)
+
+	// The job is a run of the history as well
+	run := getCronRunById(t, responseDto.JobId)
+	assert.Equal(t, entities.CronRunTriggerHttp, run.TriggeredBy)
+	assert.Equal(t, job.Processed, run.Processed)
+	assert.Equal(t, job.DurationMs, run.DurationMs)
+}
+
+func startAsyncUpdateAccountsBalances(t *testing.T) cronModuleDto.UpdateAccountsBalancesAsyncResponseDto {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/cron/account-balance?async=true", nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.CronXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+
+	var responseDto cronModuleDto.UpdateAccountsBalancesAsyncResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessStopAsync(t *testing.T) {
+	defer useImmediateRefresh()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	if !assert.Greater(t, len(accountsBefore), 0) {
+		return
+	}
+
+	// The stand-in answers only once it is released, before that only the cancel of the job ends the requests
+	var requests atomic.Int64
+	release := make(chan struct{})
+	handler := newEsploraStandInHandler(getRandomAccountsBalances(accountsBefore))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-release:
+			handler(w, r)
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	stoppedJob := startAsyncUpdateAccountsBalances(t)
+	assert.Eventually(t, func() bool {
+		return requests.Load() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	// The stop does not wait for the timeout of the accounts
+	stopStart := time.Now()
+	cronModule.StopAsyncRuns()
+	assert.Less(t, time.Since(stopStart), timeUtil.DurationSeconds(config.AppConfig.CronAccountTimeoutSec))
+
+	job := getCronJobById(t, stoppedJob.JobId)
+	assert.Equal(t, entities.CronRunStatusInterrupted, job.Status)
+	assert.Equal(t, true, job.Finished)
+
+	// The stop does not outlast the stopped jobs, the next job runs to the end
+	close(release)
+	nextJob := startAsyncUpdateAccountsBalances(t)
+	cronModule.WaitAsyncRuns()
+	job = getCronJobById(t, nextJob.JobId)
+	assert.Equal(t, entities.CronRunStatusSuccess, job.Status)
+	assert.Equal(t, len(accountsBefore), job.Processed)
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessInterruptStaleJob(t *testing.T) {
+	// The run was left running by a process that stopped a minute ago
+	startedAt := time.Now().Add(-time.Minute)
+	staleRun := entities.CreateCronRun(entities.CronRunJobAccountBalance, entities.CronRunTriggerHttp, 5, startedAt)
+	assert.Nil(t, database.CreateCronRun(nil, staleRun))
+	defer database.DbConn.Delete(staleRun)
+
+	job := getCronJobById(t, staleRun.Id)
+	assert.Equal(t, entities.CronRunStatusRunning, job.Status)
+	assert.Equal(t, false, job.Finished)
+	assert.Equal(t, 5, job.Total)
+
+	cronModule.InterruptStaleCronRuns()
+
+	job = getCronJobById(t, staleRun.Id)
+	assert.Equal(t, entities.CronRunStatusInterrupted, job.Status)
+	assert.Equal(t, true, job.Finished)
+	assert.GreaterOrEqual(t, job.FinishedAt, startedAt.Unix()+60)
+	assert.GreaterOrEqual(t, job.DurationMs, int64(60000))
+
+	runs := getCronRuns(t, url.Values{"status": []string{entities.CronRunStatusInterrupted}})
+	if assert.Greater(t, len(runs.List), 0) {
+		assert.Equal(t, staleRun.Id, runs.List[0].Id)
+	}
+}
+
+func TestGetCronJobRoute_Fail(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		method       string
+		path         string
+		apiKey       string
+		expectedCode int
+		expectedBody *errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{"FailUnauthorized", "GET", "/cron/jobs/1", "", http.StatusUnauthorized, nil},
+		{"FailInvalidId", "GET", "/cron/jobs/0", config.AppConfig.CronXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"}},
+		{"FailInvalidIdType", "GET", "/cron/jobs/first", config.AppConfig.CronXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id is invalid"}},
+		{"FailNotFound", "GET", "/cron/jobs/999999999", config.AppConfig.CronXApiKey, http.StatusNotFound, nil},
+		{"FailInvalidAsync", "POST", "/cron/account-balance?async=yes", config.AppConfig.CronXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "async is invalid"}},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetCronJobRoute"+validationTest.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest(validationTest.method, validationTest.path, nil)
+			request.Header.Set("X-API-Key", validationTest.apiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+			if validationTest.expectedBody == nil {
+				return
+			}
+
+			var responseBody errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseBody)
+			assert.Nil(t, err)
+			assert.Equal(t, *validationTest.expectedBody, responseBody)
+		})
+	}
+}
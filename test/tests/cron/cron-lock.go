@@ -0,0 +1,89 @@
+package cronTests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateAccountsBalancesRoute_FailLockedByAnotherInstance(t *testing.T) {
+	defer useImmediateRefresh()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	server := newEsploraStandInServer(getRandomAccountsBalances(accountsBefore))
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	// The lock is taken on another connection, as another instance would
+	lock, err := database.TryLock(context.Background(), entities.CronRunJobAccountBalance)
+	assert.Nil(t, err)
+	if !assert.NotNil(t, lock) {
+		return
+	}
+	otherLock, err := database.TryLock(context.Background(), entities.CronRunJobAccountBalance)
+	assert.Nil(t, err)
+	assert.Nil(t, otherLock)
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/cron/account-balance", nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.CronXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	assert.Nil(t, lock.Release())
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, len(accountsBefore), responseDto.Processed)
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessSkipClaimedAccounts(t *testing.T) {
+	defer useImmediateRefresh()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	if !assert.Greater(t, len(accountsBefore), 0) {
+		return
+	}
+
+	// Another run has claimed the first account of the batch
+	lockedUntil := timeUtil.GetUnixTime() + 600
+	claimedAccounts, err := database.ClaimAccountsBatch(1, lockedUntil)
+	assert.Nil(t, err)
+	if !assert.Equal(t, 1, len(claimedAccounts)) {
+		return
+	}
+	claimedAccount := claimedAccounts[0]
+	assert.Equal(t, accountsBefore[0].Id, claimedAccount.Id)
+	defer database.ReleaseAccounts([]int64{claimedAccount.Id}, lockedUntil)
+
+	accountsBefore = database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	for _, account := range accountsBefore {
+		assert.NotEqual(t, claimedAccount.Id, account.Id)
+	}
+
+	balances := getRandomAccountsBalances(append(accountsBefore, claimedAccount))
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, len(accountsBefore), responseDto.Processed)
+	assert.Equal(t, 0, responseDto.Failed)
+
+	// The claim of the other run is kept and the accounts of this run are released
+	claimedAccountAfter := database.GetAccountById(nil, claimedAccount.Id)
+	assert.Equal(t, claimedAccount.Balance.String(), claimedAccountAfter.Balance.String())
+	assert.Equal(t, lockedUntil, claimedAccountAfter.LockedUntil)
+	assert.Equal(t, claimedAccount.UpdatedAt, claimedAccountAfter.UpdatedAt)
+	for _, account := range accountsBefore {
+		accountAfter := database.GetAccountById(nil, account.Id)
+		assert.Equal(t, int64(0), accountAfter.LockedUntil)
+	}
+
+	assert.Nil(t, database.ReleaseAccounts([]int64{claimedAccount.Id}, lockedUntil))
+	assert.Equal(t, int64(0), database.GetAccountById(nil, claimedAccount.Id).LockedUntil)
+}
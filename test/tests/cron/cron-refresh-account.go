@@ -0,0 +1,228 @@
+package cronTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	currencyUtil "go-gin-test-job/src/utils/currency"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func refreshAccounts(t *testing.T, ids []int64) cronModuleDto.PostRefreshAccountsResponseDto {
+	body, _ := json.Marshal(cronModuleDto.PostRefreshAccountsRequestDto{Ids: ids})
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/account/refresh", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto cronModuleDto.PostRefreshAccountsResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func TestRefreshAccountRoute_Success(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	// A turned off account is refreshed as well when it is asked for
+	account, err := database.CreateAccount(nil, entities.CreateAccount("1RefreshOnDemandAccount", "On Demand Account", 10, nil, entities.AccountStatusOff))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(account)
+
+	balances := map[string]int64{account.Address: 150000000}
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", fmt.Sprintf("/account/%d/refresh", account.Id), nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto cronModuleDto.RefreshAccountResponseDto
+	err = json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	assert.Equal(t, true, responseDto.Success)
+	assert.Equal(t, account.Id, responseDto.AccountId)
+	assert.Equal(t, account.Address, responseDto.Address)
+	assert.Equal(t, "0", responseDto.OldBalance)
+	assert.Equal(t, currencyUtil.FromSatoshi(150000000).String(), responseDto.NewBalance)
+	assert.Equal(t, "0", responseDto.OldUnconfirmedBalance)
+	assert.Equal(t, currencyUtil.FromSatoshi(777).String(), responseDto.NewUnconfirmedBalance)
+	assert.Equal(t, true, responseDto.Changed)
+	assert.Equal(t, blockchain.EsploraProviderName, responseDto.Source)
+	assert.Equal(t, 2, responseDto.Transactions)
+	assert.Nil(t, responseDto.TransactionsError)
+
+	accountAfter := database.GetAccountById(nil, account.Id)
+	assert.Equal(t, responseDto.NewBalance, accountAfter.Balance.String())
+	assert.Equal(t, entities.AccountStatusOff, accountAfter.Status)
+	assert.GreaterOrEqual(t, accountAfter.LastRefreshedAt, start)
+	assert.Equal(t, int64(0), accountAfter.LockedUntil)
+}
+
+func TestRefreshAccountRoute_Fail(t *testing.T) {
+	defer useImmediateRefresh()()
+	failedAccount, err := database.CreateAccount(nil, entities.CreateAccount("1RefreshOnDemandFailedAccount", "Failed Account", 10, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(failedAccount)
+	failedOffAccount, err := database.CreateAccount(nil, entities.CreateAccount("1RefreshOnDemandFailedOffAccount", "Failed Off Account", 10, nil, entities.AccountStatusOff))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(failedOffAccount)
+	claimedAccount, err := database.CreateAccount(nil, entities.CreateAccount("1RefreshOnDemandClaimedAccount", "Claimed Account", 10, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(claimedAccount)
+	lockedUntil := timeUtil.GetUnixTime() + 600
+	claimedAccounts, err := database.ClaimAccountsByIds([]int64{claimedAccount.Id}, lockedUntil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(claimedAccounts))
+	defer database.ReleaseAccounts([]int64{claimedAccount.Id}, lockedUntil)
+
+	// The provider knows only the claimed account
+	server := newEsploraStandInServer(map[string]int64{claimedAccount.Address: 100})
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	validationTests := []struct {
+		name          string
+		path          string
+		apiKey        string
+		expectedCode  int
+		expectedError string
+	}{
+		{"FailUnauthorized", fmt.Sprintf("/account/%d/refresh", failedAccount.Id), "", http.StatusUnauthorized, ""},
+		{"FailInvalidId", "/account/0/refresh", config.AppConfig.AdminXApiKey, http.StatusBadRequest, "Id must be greater than or equal 1"},
+		{"FailNotFound", "/account/999999999/refresh", config.AppConfig.AdminXApiKey, http.StatusNotFound, "Account not found"},
+		{"FailClaimed", fmt.Sprintf("/account/%d/refresh", claimedAccount.Id), config.AppConfig.AdminXApiKey, http.StatusConflict, "Account is being refreshed"},
+		{"FailProvider", fmt.Sprintf("/account/%d/refresh", failedAccount.Id), config.AppConfig.AdminXApiKey, http.StatusBadGateway, "Account balance is not available. "},
+		{"FailProviderAccountOff", fmt.Sprintf("/account/%d/refresh", failedOffAccount.Id), config.AppConfig.AdminXApiKey, http.StatusBadGateway, "Account balance is not available. "},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestRefreshAccountRoute"+validationTest.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", validationTest.path, nil)
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-API-Key", validationTest.apiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+			if validationTest.expectedError == "" {
+				return
+			}
+
+			var responseBody errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseBody)
+			assert.Nil(t, err)
+			assert.Equal(t, false, responseBody.Success)
+			assert.True(t, strings.HasPrefix(responseBody.Message, validationTest.expectedError), responseBody.Message)
+		})
+	}
+
+	// The failure is counted like in a cron run and the claimed account is left as it is
+	failedAccountAfter := database.GetAccountById(nil, failedAccount.Id)
+	assert.Equal(t, 1, failedAccountAfter.ConsecutiveFailures)
+	assert.NotNil(t, failedAccountAfter.LastError)
+	// The failure of an account turned off is not counted
+	failedOffAccountAfter := database.GetAccountById(nil, failedOffAccount.Id)
+	assert.Equal(t, 0, failedOffAccountAfter.ConsecutiveFailures)
+	assert.Nil(t, failedOffAccountAfter.LastError)
+	assert.Equal(t, int64(0), failedOffAccountAfter.LockedUntil)
+	claimedAccountAfter := database.GetAccountById(nil, claimedAccount.Id)
+	assert.Equal(t, lockedUntil, claimedAccountAfter.LockedUntil)
+	assert.Equal(t, claimedAccount.Balance.String(), claimedAccountAfter.Balance.String())
+}
+
+func TestRefreshAccountsRoute_Success(t *testing.T) {
+	defer useImmediateRefresh()()
+	accounts := make([]*entities.Account, 0, 3)
+	for _, address := range []string{"1RefreshBulkAccount", "1RefreshBulkFailedAccount", "1RefreshBulkClaimedAccount"} {
+		account, err := database.CreateAccount(nil, entities.CreateAccount(address, "Bulk Account", 10, nil, entities.AccountStatusOn))
+		assert.Nil(t, err)
+		defer database.DbConn.Delete(account)
+		accounts = append(accounts, account)
+	}
+	refreshedAccount, failedAccount, claimedAccount := accounts[0], accounts[1], accounts[2]
+	lockedUntil := timeUtil.GetUnixTime() + 600
+	_, err := database.ClaimAccountsByIds([]int64{claimedAccount.Id}, lockedUntil)
+	assert.Nil(t, err)
+	defer database.ReleaseAccounts([]int64{claimedAccount.Id}, lockedUntil)
+
+	balances := map[string]int64{refreshedAccount.Address: 2500, claimedAccount.Address: 100}
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	responseDto := refreshAccounts(t, []int64{claimedAccount.Id, 999999999, refreshedAccount.Id, failedAccount.Id})
+	assert.Equal(t, 1, responseDto.Refreshed)
+	assert.Equal(t, 3, responseDto.Failed)
+	if !assert.Equal(t, 4, len(responseDto.Results)) {
+		return
+	}
+	assert.Equal(t, cronModuleDto.RefreshAccountsItemResultDto{AccountId: claimedAccount.Id, Status: cronModuleDto.RefreshAccountsItemStatusBusy}, responseDto.Results[0])
+	assert.Equal(t, cronModuleDto.RefreshAccountsItemResultDto{AccountId: 999999999, Status: cronModuleDto.RefreshAccountsItemStatusNotFound}, responseDto.Results[1])
+	assert.Equal(t, refreshedAccount.Id, responseDto.Results[2].AccountId)
+	assert.Equal(t, cronModuleDto.RefreshAccountsItemStatusRefreshed, responseDto.Results[2].Status)
+	if assert.NotNil(t, responseDto.Results[2].Refresh) {
+		assert.Equal(t, "0", responseDto.Results[2].Refresh.OldBalance)
+		assert.Equal(t, currencyUtil.FromSatoshi(2500).String(), responseDto.Results[2].Refresh.NewBalance)
+	}
+	assert.Equal(t, failedAccount.Id, responseDto.Results[3].AccountId)
+	assert.Equal(t, cronModuleDto.RefreshAccountsItemStatusFailed, responseDto.Results[3].Status)
+	assert.NotEmpty(t, responseDto.Results[3].Error)
+	assert.Nil(t, responseDto.Results[3].Refresh)
+
+	assertAccountsBalances(t, []*entities.Account{refreshedAccount}, balances, blockchain.EsploraProviderName, refreshedAccount.CreatedAt)
+	// The accounts of the request are released, the claim of the other one is kept
+	assert.Equal(t, int64(0), database.GetAccountById(nil, refreshedAccount.Id).LockedUntil)
+	assert.Equal(t, int64(0), database.GetAccountById(nil, failedAccount.Id).LockedUntil)
+	assert.Equal(t, lockedUntil, database.GetAccountById(nil, claimedAccount.Id).LockedUntil)
+}
+
+func TestRefreshAccountsRoute_Fail(t *testing.T) {
+	tooManyIds := make([]string, 0, 101)
+	for id := 1; id <= 101; id++ {
+		tooManyIds = append(tooManyIds, fmt.Sprintf("%d", id))
+	}
+	validationTests := []struct {
+		name          string
+		jsonParams    string
+		expectedError string
+	}{
+		{"FailEmptyIds", `{"ids": []}`, "Ids must contain at least 1 item"},
+		{"FailMissingIds", `{}`, "Ids must contain at least 1 item"},
+		{"FailTooManyIds", fmt.Sprintf(`{"ids": [%s]}`, strings.Join(tooManyIds, ",")), "Ids must contain at most 100 items"},
+		{"FailDuplicateIds", `{"ids": [1, 1]}`, "Ids must not contain duplicates"},
+		{"FailInvalidId", `{"ids": [1, 0]}`, "Every id must be greater than or equal 1"},
+		{"FailInvalidIdType", `{"ids": ["first"]}`, "Invalid request query"},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestRefreshAccountsRoute"+validationTest.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/account/refresh", bytes.NewBufferString(validationTest.jsonParams))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+
+			var responseBody errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseBody)
+			assert.Nil(t, err)
+			assert.Equal(t, errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: validationTest.expectedError}, responseBody)
+		})
+	}
+}
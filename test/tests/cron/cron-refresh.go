@@ -0,0 +1,140 @@
+package cronTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	accountModuleDto "go-gin-test-job/src/modules/account/dto"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// resetAccountsRefresh makes every account due again for the next tests
+func resetAccountsRefresh() {
+	database.DbConn.Model(entities.Account{}).Where("next_refresh_at <> 0").UpdateColumn("next_refresh_at", 0)
+}
+
+// useImmediateRefresh makes every account due right away and returns the restore of the config
+func useImmediateRefresh() func() {
+	resetAccountsRefresh()
+	defaultCronRefresh := config.AppConfig.CronRefresh
+	config.AppConfig.CronRefresh = config.CronRefreshConfig{}
+	return func() {
+		config.AppConfig.CronRefresh = defaultCronRefresh
+	}
+}
+
+func getAccount(t *testing.T, id int64) accountModuleDto.AccountDto {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", fmt.Sprintf("/account/%d", id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto accountModuleDto.AccountDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessRefreshSchedule(t *testing.T) {
+	defer useAllAccountsBatch()()
+	defaultCronRefresh := config.AppConfig.CronRefresh
+	defer func() {
+		config.AppConfig.CronRefresh = defaultCronRefresh
+	}()
+	config.AppConfig.CronRefresh = config.CronRefreshConfig{IntervalSec: 3600, MinIntervalSec: 300, MaxBackoffSec: 10000, MaxFailures: 2}
+	defer resetAccountsRefresh()
+
+	topRankAccount, err := database.CreateAccount(nil, entities.CreateAccount("1RefreshTopRankAccount", "Top Rank Account", 100, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(topRankAccount)
+	ownIntervalAccount := entities.CreateAccount("1RefreshOwnIntervalAccount", "Own Interval Account", 100, nil, entities.AccountStatusOn)
+	ownIntervalAccount.RefreshIntervalSec = 600
+	ownIntervalAccount, err = database.CreateAccount(nil, ownIntervalAccount)
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(ownIntervalAccount)
+	// The provider does not know the address, so every refresh of the account fails
+	failedAccount, err := database.CreateAccount(nil, entities.CreateAccount("1RefreshFailedAccount", "Failed Account", 0, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(failedAccount)
+	failedAccount = database.GetAccountById(nil, failedAccount.Id)
+
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	refreshedAccounts := make([]*entities.Account, 0, len(accountsBefore))
+	for _, account := range accountsBefore {
+		if account.Id != failedAccount.Id {
+			refreshedAccounts = append(refreshedAccounts, account)
+		}
+	}
+	assert.Equal(t, len(accountsBefore)-1, len(refreshedAccounts))
+	server := newEsploraStandInServer(getRandomAccountsBalances(refreshedAccounts))
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	start := timeUtil.GetUnixTime()
+	responseDto := runUpdateAccountsBalances(t)
+	end := timeUtil.GetUnixTime()
+	assert.Equal(t, len(accountsBefore), responseDto.Processed)
+	assert.Equal(t, 1, responseDto.Failed)
+
+	// The interval goes down with the rank unless the account has its own one
+	topRankAccountAfter := database.GetAccountById(nil, topRankAccount.Id)
+	assert.GreaterOrEqual(t, topRankAccountAfter.LastRefreshedAt, start)
+	assert.Equal(t, topRankAccountAfter.LastRefreshedAt+300, topRankAccountAfter.NextRefreshAt)
+	assert.Equal(t, 0, topRankAccountAfter.ConsecutiveFailures)
+	assert.Nil(t, topRankAccountAfter.LastError)
+	ownIntervalAccountAfter := database.GetAccountById(nil, ownIntervalAccount.Id)
+	assert.Equal(t, ownIntervalAccountAfter.LastRefreshedAt+600, ownIntervalAccountAfter.NextRefreshAt)
+	for _, account := range refreshedAccounts {
+		accountAfter := database.GetAccountById(nil, account.Id)
+		assert.Greater(t, accountAfter.NextRefreshAt, end)
+	}
+
+	// The failure doubles the interval of rank 0 and does not change the account
+	failedAccountAfter := database.GetAccountById(nil, failedAccount.Id)
+	assert.Equal(t, 1, failedAccountAfter.ConsecutiveFailures)
+	assert.NotNil(t, failedAccountAfter.LastError)
+	assert.Equal(t, int64(0), failedAccountAfter.LastRefreshedAt)
+	assert.GreaterOrEqual(t, failedAccountAfter.NextRefreshAt, start+7200)
+	assert.LessOrEqual(t, failedAccountAfter.NextRefreshAt, end+7200)
+	assert.Equal(t, entities.AccountStatusOn, failedAccountAfter.Status)
+	assert.Equal(t, failedAccount.UpdatedAt, failedAccountAfter.UpdatedAt)
+	test.CompareAccount(t, failedAccountAfter, getAccount(t, failedAccount.Id))
+
+	// Nothing is due until the next refresh
+	assert.Equal(t, 0, len(database.GetAccountsBatch(config.AppConfig.CronBatchCount)))
+
+	// The second failure in a row reaches the backoff limit and turns the account off
+	assert.Nil(t, database.UpdateAccountColumns(nil, failedAccountAfter, map[string]interface{}{"NextRefreshAt": 0}))
+	responseDto = runUpdateAccountsBalances(t)
+	assert.Equal(t, 1, responseDto.Processed)
+	assert.Equal(t, 1, responseDto.Failed)
+	failedAccountAfter = database.GetAccountById(nil, failedAccount.Id)
+	assert.Equal(t, 2, failedAccountAfter.ConsecutiveFailures)
+	assert.Equal(t, entities.AccountStatusOff, failedAccountAfter.Status)
+	assert.GreaterOrEqual(t, failedAccountAfter.NextRefreshAt, start+10000)
+	test.CompareAccount(t, failedAccountAfter, getAccount(t, failedAccount.Id))
+
+	// Turned on again the account starts without failures and is due right away
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("PATCH", fmt.Sprintf("/account/%d", failedAccount.Id), bytes.NewBufferString(`{"status": "On"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	failedAccountAfter = database.GetAccountById(nil, failedAccount.Id)
+	assert.Equal(t, 0, failedAccountAfter.ConsecutiveFailures)
+	assert.Equal(t, int64(0), failedAccountAfter.NextRefreshAt)
+	accountsAfter := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	if assert.Equal(t, 1, len(accountsAfter)) {
+		assert.Equal(t, failedAccount.Id, accountsAfter[0].Id)
+	}
+}
@@ -0,0 +1,197 @@
+package cronTests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	errorHelpers "go-gin-test-job/src/common/error-helpers"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func getCronRuns(t *testing.T, query url.Values) cronModuleDto.GetCronRunsResponseDto {
+	u := &url.URL{
+		Path:     "/cron/runs",
+		RawQuery: query.Encode(),
+	}
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", u.String(), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto cronModuleDto.GetCronRunsResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+func getCronRunById(t *testing.T, id int64) cronModuleDto.GetCronRunResponseDto {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", fmt.Sprintf("/cron/runs/%d", id), nil)
+	request.Header.Set("X-API-Key", config.AppConfig.AdminXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var responseDto cronModuleDto.GetCronRunResponseDto
+	err := json.NewDecoder(response.Body).Decode(&responseDto)
+	assert.Nil(t, err)
+	return responseDto
+}
+
+// useAllAccountsBatch makes a run take every active account, so that two runs in a row see the same accounts
+func useAllAccountsBatch() func() {
+	defaultBatchCount := config.AppConfig.CronBatchCount
+	config.AppConfig.CronBatchCount = 1000
+	return func() {
+		config.AppConfig.CronBatchCount = defaultBatchCount
+	}
+}
+
+func TestUpdateAccountsBalancesRoute_SuccessRunReport(t *testing.T) {
+	defer useImmediateRefresh()()
+	defer useAllAccountsBatch()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	balances := getRandomAccountsBalances(accountsBefore)
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	firstRun := runUpdateAccountsBalances(t)
+	assert.Equal(t, entities.CronRunStatusSuccess, firstRun.Status)
+	assert.Equal(t, len(accountsBefore), firstRun.Processed)
+	assert.Equal(t, len(accountsBefore), firstRun.Updated)
+	assert.Equal(t, 0, firstRun.Unchanged)
+	historiesTotals := make(map[int64]int64)
+	for _, account := range accountsBefore {
+		_, historiesTotals[account.Id] = database.GetAccountBalanceHistoryAndTotal(database.AccountBalanceHistoryFilter{AccountId: account.Id}, 0, 1)
+	}
+
+	// The same balances again do not change the accounts
+	secondRun := runUpdateAccountsBalances(t)
+	assert.Greater(t, secondRun.RunId, firstRun.RunId)
+	assert.Equal(t, entities.CronRunStatusSuccess, secondRun.Status)
+	assert.Equal(t, len(accountsBefore), secondRun.Processed)
+	assert.Equal(t, 0, secondRun.Updated)
+	assert.Equal(t, len(accountsBefore), secondRun.Unchanged)
+	assert.Equal(t, 0, secondRun.Failed)
+	assert.LessOrEqual(t, secondRun.StartedAt, secondRun.FinishedAt)
+	assert.GreaterOrEqual(t, secondRun.DurationMs, int64(0))
+	// An unchanged balance writes no snapshot
+	for _, account := range accountsBefore {
+		_, total := database.GetAccountBalanceHistoryAndTotal(database.AccountBalanceHistoryFilter{AccountId: account.Id}, 0, 1)
+		assert.Equal(t, historiesTotals[account.Id], total)
+	}
+
+	run := getCronRunById(t, secondRun.RunId)
+	assert.Equal(t, secondRun.RunId, run.Id)
+	assert.Equal(t, entities.CronRunJobAccountBalance, run.Job)
+	assert.Equal(t, entities.CronRunTriggerHttp, run.TriggeredBy)
+	assert.Equal(t, entities.CronRunStatusSuccess, run.Status)
+	assert.Equal(t, secondRun.Processed, run.Processed)
+	assert.Equal(t, secondRun.Unchanged, run.Unchanged)
+	assert.Equal(t, secondRun.Transactions, run.Transactions)
+	assert.Equal(t, secondRun.StartedAt, run.StartedAt)
+	assert.Equal(t, secondRun.DurationMs, run.DurationMs)
+	assert.Equal(t, 0, len(run.Errors))
+	assert.Equal(t, 0, len(run.Disagreements))
+
+	runs := getCronRuns(t, url.Values{"status": []string{entities.CronRunStatusSuccess}, "count": []string{"2"}})
+	assert.Equal(t, 2, runs.Count)
+	assert.GreaterOrEqual(t, runs.Total, int64(2))
+	if assert.Equal(t, 2, len(runs.List)) {
+		assert.Equal(t, secondRun.RunId, runs.List[0].Id)
+		assert.Equal(t, firstRun.RunId, runs.List[1].Id)
+	}
+}
+
+func TestUpdateAccountsBalancesRoute_FailRunReport(t *testing.T) {
+	defer useImmediateRefresh()()
+	defer useAllAccountsBatch()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	// No balances, the provider does not know any address
+	server := newEsploraStandInServer(map[string]int64{})
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, entities.CronRunStatusFailed, responseDto.Status)
+	assert.Equal(t, len(accountsBefore), responseDto.Processed)
+	assert.Equal(t, len(accountsBefore), responseDto.Failed)
+	assert.Equal(t, len(accountsBefore)*2, len(responseDto.Errors))
+
+	run := getCronRunById(t, responseDto.RunId)
+	assert.Equal(t, entities.CronRunStatusFailed, run.Status)
+	assert.Equal(t, len(accountsBefore), run.Failed)
+	assert.Equal(t, len(accountsBefore), run.TransactionsFailed)
+	if assert.Equal(t, len(responseDto.Errors), len(run.Errors)) {
+		for index, accountError := range responseDto.Errors {
+			assert.Equal(t, accountError, run.Errors[index])
+		}
+	}
+
+	runs := getCronRuns(t, url.Values{"status": []string{entities.CronRunStatusFailed}, "job": []string{entities.CronRunJobAccountBalance}, "triggeredBy": []string{entities.CronRunTriggerHttp}})
+	if assert.Greater(t, len(runs.List), 0) {
+		assert.Equal(t, responseDto.RunId, runs.List[0].Id)
+	}
+	for _, listRun := range runs.List {
+		assert.Equal(t, entities.CronRunStatusFailed, listRun.Status)
+	}
+	runs = getCronRuns(t, url.Values{"triggeredBy": []string{entities.CronRunTriggerScheduler}, "status": []string{entities.CronRunStatusFailed}})
+	for _, listRun := range runs.List {
+		assert.Equal(t, entities.CronRunTriggerScheduler, listRun.TriggeredBy)
+		assert.NotEqual(t, responseDto.RunId, listRun.Id)
+	}
+}
+
+func TestGetCronRunsRoute_Fail(t *testing.T) {
+	validationTests := []struct {
+		name         string
+		path         string
+		query        url.Values
+		apiKey       string
+		expectedCode int
+		expectedBody *errorHelpers.ResponseBadRequestErrorHTTP
+	}{
+		{"FailUnauthorized", "/cron/runs", url.Values{}, "", http.StatusUnauthorized, nil},
+		{"FailInvalidStatus", "/cron/runs", url.Values{"status": []string{"unknown"}}, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Status must be one of the next values: running,success,partial,failed,interrupted"}},
+		{"FailInvalidTriggeredBy", "/cron/runs", url.Values{"triggeredBy": []string{"cron"}}, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "TriggeredBy must be one of the next values: http,scheduler"}},
+		{"FailInvalidCountValue", "/cron/runs", url.Values{"count": []string{"101"}}, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Count must be less than or equal 100"}},
+		{"FailInvalidOffsetType", "/cron/runs", url.Values{"offset": []string{"first"}}, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "offset is invalid"}},
+		{"FailInvalidId", "/cron/runs/0", url.Values{}, config.AppConfig.AdminXApiKey, http.StatusBadRequest, &errorHelpers.ResponseBadRequestErrorHTTP{Success: false, Message: "Id must be greater than or equal 1"}},
+		{"FailNotFound", "/cron/runs/999999999", url.Values{}, config.AppConfig.AdminXApiKey, http.StatusNotFound, nil},
+	}
+	for _, validationTest := range validationTests {
+		t.Run("TestGetCronRunsRoute"+validationTest.name, func(t *testing.T) {
+			u := &url.URL{
+				Path:     validationTest.path,
+				RawQuery: validationTest.query.Encode(),
+			}
+
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", u.String(), nil)
+			request.Header.Set("X-API-Key", validationTest.apiKey)
+			test.TestApp.ServeHTTP(response, request)
+			assert.Equal(t, validationTest.expectedCode, response.Code)
+			if validationTest.expectedBody == nil {
+				return
+			}
+
+			var responseBody errorHelpers.ResponseBadRequestErrorHTTP
+			err := json.NewDecoder(response.Body).Decode(&responseBody)
+			assert.Nil(t, err)
+			assert.Equal(t, *validationTest.expectedBody, responseBody)
+		})
+	}
+}
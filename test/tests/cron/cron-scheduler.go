@@ -0,0 +1,199 @@
+package cronTests
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModule "go-gin-test-job/src/modules/cron"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"go-gin-test-job/test"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// onceSchedule fires right away and then not before the end of the test
+type onceSchedule struct {
+	fired bool
+}
+
+func (s *onceSchedule) Next(after time.Time) time.Time {
+	if s.fired {
+		return after.Add(time.Hour)
+	}
+	s.fired = true
+	return after
+}
+
+// getLastSchedulerRunId is 0 before the first scheduled run
+func getLastSchedulerRunId() int64 {
+	runs, _ := database.GetCronRunsAndTotal(database.CronRunFilter{Job: entities.CronRunJobAccountBalance, TriggeredBy: entities.CronRunTriggerScheduler}, 0, 1)
+	if len(runs) == 0 {
+		return 0
+	}
+	return runs[0].Id
+}
+
+// waitSchedulerRun waits for a scheduled run newer than lastRunId to be stored with the expected status
+func waitSchedulerRun(t *testing.T, lastRunId int64, status string) *entities.CronRun {
+	var run *entities.CronRun
+	assert.Eventually(t, func() bool {
+		runId := getLastSchedulerRunId()
+		if runId <= lastRunId {
+			return false
+		}
+		run = database.GetCronRunById(runId)
+		return run != nil && run.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return run
+}
+
+func TestCronScheduler_Success(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	lastRunId := getLastSchedulerRunId()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	balances := getRandomAccountsBalances(accountsBefore)
+	server := newEsploraStandInServer(balances)
+	defer server.Close()
+
+	scheduler := cronModule.NewScheduler(context.Background(), &onceSchedule{}, newBalanceResolver(t, []*httptest.Server{server}, 0))
+	scheduler.Start()
+	run := waitSchedulerRun(t, lastRunId, entities.CronRunStatusSuccess)
+	scheduler.Stop()
+
+	if assert.NotNil(t, run) {
+		assert.Equal(t, len(accountsBefore), run.Total)
+		assert.Equal(t, len(accountsBefore), run.Processed)
+	}
+	assertAccountsBalances(t, accountsBefore, balances, blockchain.EsploraProviderName, start)
+}
+
+func TestCronScheduler_SuccessStopInterruptsRun(t *testing.T) {
+	testSchedulerInterruptsRun(t, func(scheduler *cronModule.Scheduler, cancel context.CancelFunc) {
+		scheduler.Stop()
+	})
+}
+
+func TestCronScheduler_SuccessCancelInterruptsRun(t *testing.T) {
+	testSchedulerInterruptsRun(t, func(scheduler *cronModule.Scheduler, cancel context.CancelFunc) {
+		cancel()
+		scheduler.Stop()
+	})
+}
+
+// testSchedulerInterruptsRun stops the scheduler with stop while its run waits for the provider
+func testSchedulerInterruptsRun(t *testing.T, stop func(scheduler *cronModule.Scheduler, cancel context.CancelFunc)) {
+	defer useImmediateRefresh()()
+	lastRunId := getLastSchedulerRunId()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	// The stand-in never answers, only the cancel of the run ends the requests
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler := cronModule.NewScheduler(ctx, &onceSchedule{}, newBalanceResolver(t, []*httptest.Server{server}, 0))
+	scheduler.Start()
+	assert.Eventually(t, func() bool {
+		return requests.Load() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	// The stop does not wait for the timeout of the accounts
+	stopStart := time.Now()
+	stop(scheduler, cancel)
+	assert.Less(t, time.Since(stopStart), timeUtil.DurationSeconds(config.AppConfig.CronAccountTimeoutSec))
+
+	run := waitSchedulerRun(t, lastRunId, entities.CronRunStatusInterrupted)
+	if assert.NotNil(t, run) {
+		assert.Equal(t, len(accountsBefore), run.Total)
+		assert.LessOrEqual(t, run.Processed, run.Total)
+		assert.Greater(t, run.FinishedAt, int64(0))
+	}
+	// The cancel is not a failure of the accounts, their balances and their refresh plans are kept
+	for _, accountBefore := range accountsBefore {
+		accountAfter := database.GetAccountById(nil, accountBefore.Id)
+		assert.Equal(t, accountBefore.Balance.String(), accountAfter.Balance.String())
+		assert.Equal(t, accountBefore.ConsecutiveFailures, accountAfter.ConsecutiveFailures)
+		assert.Equal(t, accountBefore.NextRefreshAt, accountAfter.NextRefreshAt)
+		assert.Equal(t, int64(0), accountAfter.LockedUntil)
+	}
+}
+
+func TestCronScheduler_FailAlreadyRunning(t *testing.T) {
+	defer useImmediateRefresh()()
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	assert.Greater(t, len(accountsBefore), 0)
+
+	// The stand-in holds the scheduled run until it is released
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var startedOnce sync.Once
+	handler := newEsploraStandInHandler(getRandomAccountsBalances(accountsBefore))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedOnce.Do(func() {
+			close(started)
+		})
+		<-release
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	scheduler := cronModule.NewScheduler(context.Background(), &onceSchedule{}, newBalanceResolver(t, []*httptest.Server{server}, 0))
+	scheduler.Start()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scheduled run has not started")
+	}
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/cron/account-balance", nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", config.AppConfig.CronXApiKey)
+	test.TestApp.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	close(release)
+	scheduler.Stop()
+}
+
+func TestCronScheduler_Config(t *testing.T) {
+	scheduler, err := cronModule.NewSchedulerFromConfig(context.Background(), config.CronSchedulerConfig{}, blockchain.Resolver)
+	assert.Nil(t, err)
+	assert.Nil(t, scheduler)
+
+	scheduler, err = cronModule.NewSchedulerFromConfig(context.Background(), config.CronSchedulerConfig{Expression: "*/5 * * * *"}, blockchain.Resolver)
+	assert.Nil(t, err)
+	assert.NotNil(t, scheduler)
+
+	scheduler, err = cronModule.NewSchedulerFromConfig(context.Background(), config.CronSchedulerConfig{IntervalSec: 60}, blockchain.Resolver)
+	assert.Nil(t, err)
+	assert.NotNil(t, scheduler)
+
+	for _, schedulerConfig := range []config.CronSchedulerConfig{
+		{Expression: "*/5 * * *"},
+		{Expression: "60 * * * *"},
+		{Expression: "0 0 30 2 *"},
+		{Expression: "0 0 * * 1-5/0"},
+		{IntervalSec: -1},
+		{Expression: "*/5 * * * *", IntervalSec: 60},
+	} {
+		scheduler, err = cronModule.NewSchedulerFromConfig(context.Background(), schedulerConfig, blockchain.Resolver)
+		assert.NotNil(t, err, schedulerConfig.Expression)
+		assert.Nil(t, scheduler)
+	}
+}
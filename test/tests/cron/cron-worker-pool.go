@@ -0,0 +1,91 @@
+package cronTests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go-gin-test-job/src/config"
+	"go-gin-test-job/src/database"
+	"go-gin-test-job/src/database/entities"
+	"go-gin-test-job/src/modules/common/blockchain"
+	cronModuleDto "go-gin-test-job/src/modules/cron/dto"
+	timeUtil "go-gin-test-job/src/utils/time"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateAccountsBalancesRoute_SuccessConcurrent(t *testing.T) {
+	defer useImmediateRefresh()()
+	start := timeUtil.GetUnixTime()
+	defaultBatchCount, defaultConcurrency, defaultAccountTimeoutSec := config.AppConfig.CronBatchCount, config.AppConfig.CronConcurrency, config.AppConfig.CronAccountTimeoutSec
+	defer func() {
+		config.AppConfig.CronBatchCount = defaultBatchCount
+		config.AppConfig.CronConcurrency = defaultConcurrency
+		config.AppConfig.CronAccountTimeoutSec = defaultAccountTimeoutSec
+	}()
+	config.AppConfig.CronBatchCount = 100
+	config.AppConfig.CronConcurrency = 4
+	config.AppConfig.CronAccountTimeoutSec = 1
+
+	// One account is unknown to the provider and the other one does not answer before the timeout
+	failedAccount, err := database.CreateAccount(nil, entities.CreateAccount("1WorkerPoolFailedAccount", "Failed Account", 10, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(failedAccount)
+	slowAccount, err := database.CreateAccount(nil, entities.CreateAccount("1WorkerPoolSlowAccount", "Slow Account", 10, nil, entities.AccountStatusOn))
+	assert.Nil(t, err)
+	defer database.DbConn.Delete(slowAccount)
+
+	accountsBefore := database.GetAccountsBatch(config.AppConfig.CronBatchCount)
+	updatedAccounts := make([]*entities.Account, 0, len(accountsBefore))
+	for _, account := range accountsBefore {
+		if account.Id != failedAccount.Id && account.Id != slowAccount.Id {
+			updatedAccounts = append(updatedAccounts, account)
+		}
+	}
+	assert.Equal(t, len(accountsBefore)-2, len(updatedAccounts))
+	assert.Greater(t, len(updatedAccounts), 0)
+	balances := getRandomAccountsBalances(updatedAccounts)
+	handler := newEsploraStandInHandler(balances)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, slowAccount.Address) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		handler(w, r)
+	}))
+	defer server.Close()
+	defer useBalanceResolver(t, []*httptest.Server{server}, 0)()
+
+	responseDto := runUpdateAccountsBalances(t)
+	assert.Equal(t, len(updatedAccounts), responseDto.Updated)
+	assert.Equal(t, 2, responseDto.Failed)
+	assert.Equal(t, len(updatedAccounts)*2, responseDto.Transactions)
+	assert.Equal(t, 2, responseDto.TransactionsFailed)
+	assertAccountsBalances(t, updatedAccounts, balances, blockchain.EsploraProviderName, start)
+
+	// Every failed account has an error for each stage
+	if assert.Equal(t, 4, len(responseDto.Errors)) {
+		errors := make(map[int64]map[string]string)
+		for _, accountError := range responseDto.Errors {
+			if errors[accountError.AccountId] == nil {
+				errors[accountError.AccountId] = make(map[string]string)
+			}
+			errors[accountError.AccountId][accountError.Stage] = accountError.Error
+		}
+		for _, account := range []*entities.Account{failedAccount, slowAccount} {
+			assert.NotEmpty(t, errors[account.Id][cronModuleDto.AccountUpdateStageBalance])
+			assert.NotEmpty(t, errors[account.Id][cronModuleDto.AccountUpdateStageTransactions])
+		}
+		assert.Contains(t, errors[slowAccount.Id][cronModuleDto.AccountUpdateStageBalance], "deadline exceeded")
+	}
+
+	// The accounts that failed keep their balance
+	for _, accountBefore := range []*entities.Account{failedAccount, slowAccount} {
+		accountAfter := database.GetAccountById(nil, accountBefore.Id)
+		assert.Equal(t, accountBefore.Balance.String(), accountAfter.Balance.String())
+	}
+}
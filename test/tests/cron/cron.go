@@ -23,6 +23,35 @@ import (
 
 func TestCronRoute(t *testing.T) {
 	t.Run("TestUpdateAccountsBalancesRoute_Success", TestUpdateAccountsBalancesRoute_Success)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessBitcoreProvider", TestUpdateAccountsBalancesRoute_SuccessBitcoreProvider)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessEsploraProvider", TestUpdateAccountsBalancesRoute_SuccessEsploraProvider)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessFailover", TestUpdateAccountsBalancesRoute_SuccessFailover)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessQuorum", TestUpdateAccountsBalancesRoute_SuccessQuorum)
+	t.Run("TestUpdateAccountsBalancesRoute_FailQuorumDisagreement", TestUpdateAccountsBalancesRoute_FailQuorumDisagreement)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessRetry", TestUpdateAccountsBalancesRoute_SuccessRetry)
+	t.Run("TestUpdateAccountsBalancesRoute_FailInvalidResponse", TestUpdateAccountsBalancesRoute_FailInvalidResponse)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessTransactions", TestUpdateAccountsBalancesRoute_SuccessTransactions)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessConcurrent", TestUpdateAccountsBalancesRoute_SuccessConcurrent)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessRunReport", TestUpdateAccountsBalancesRoute_SuccessRunReport)
+	t.Run("TestUpdateAccountsBalancesRoute_FailRunReport", TestUpdateAccountsBalancesRoute_FailRunReport)
+	t.Run("TestGetCronRunsRoute_Fail", TestGetCronRunsRoute_Fail)
+	t.Run("TestUpdateAccountsBalancesRoute_FailLockedByAnotherInstance", TestUpdateAccountsBalancesRoute_FailLockedByAnotherInstance)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessSkipClaimedAccounts", TestUpdateAccountsBalancesRoute_SuccessSkipClaimedAccounts)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessRefreshSchedule", TestUpdateAccountsBalancesRoute_SuccessRefreshSchedule)
+	t.Run("TestRefreshAccountRoute_Success", TestRefreshAccountRoute_Success)
+	t.Run("TestRefreshAccountRoute_Fail", TestRefreshAccountRoute_Fail)
+	t.Run("TestRefreshAccountsRoute_Success", TestRefreshAccountsRoute_Success)
+	t.Run("TestRefreshAccountsRoute_Fail", TestRefreshAccountsRoute_Fail)
+	t.Run("TestRefreshAccountRoute_SuccessBitcoreTransactionsPerRun", TestRefreshAccountRoute_SuccessBitcoreTransactionsPerRun)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessAsync", TestUpdateAccountsBalancesRoute_SuccessAsync)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessStopAsync", TestUpdateAccountsBalancesRoute_SuccessStopAsync)
+	t.Run("TestUpdateAccountsBalancesRoute_SuccessInterruptStaleJob", TestUpdateAccountsBalancesRoute_SuccessInterruptStaleJob)
+	t.Run("TestGetCronJobRoute_Fail", TestGetCronJobRoute_Fail)
+	t.Run("TestCronScheduler_Success", TestCronScheduler_Success)
+	t.Run("TestCronScheduler_SuccessStopInterruptsRun", TestCronScheduler_SuccessStopInterruptsRun)
+	t.Run("TestCronScheduler_SuccessCancelInterruptsRun", TestCronScheduler_SuccessCancelInterruptsRun)
+	t.Run("TestCronScheduler_FailAlreadyRunning", TestCronScheduler_FailAlreadyRunning)
+	t.Run("TestCronScheduler_Config", TestCronScheduler_Config)
 }
 
 func TestUpdateAccountsBalancesRoute_Success(t *testing.T) {